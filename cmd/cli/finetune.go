@@ -0,0 +1,205 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/cinience/aigo-kode/internal/config"
+	"github.com/cinience/aigo-kode/internal/finetune"
+)
+
+// runFinetuneCommand dispatches `kode finetune <subcommand>`, managing
+// OpenAI fine-tuning jobs from the command line.
+func runFinetuneCommand(args []string, cfg *config.FileConfig, globalConfig *config.GlobalConfig) error {
+	if len(args) == 0 {
+		return errors.New("usage: kode finetune <create|list|retrieve|cancel|events> [args]")
+	}
+
+	tuner, err := newFinetuner(globalConfig)
+	if err != nil {
+		return err
+	}
+
+	switch args[0] {
+	case "create":
+		return runFinetuneCreate(tuner, cfg, globalConfig, args[1:])
+	case "list":
+		return runFinetuneList(tuner, args[1:])
+	case "retrieve":
+		return runFinetuneRetrieve(tuner, args[1:])
+	case "cancel":
+		return runFinetuneCancel(tuner, args[1:])
+	case "events":
+		return runFinetuneEvents(tuner, args[1:])
+	default:
+		return fmt.Errorf("unknown finetune subcommand %q", args[0])
+	}
+}
+
+// newFinetuner builds an OpenAI fine-tuner from the saved provider config,
+// honoring the OPENAI_API_KEY env override the same way main does.
+func newFinetuner(globalConfig *config.GlobalConfig) (finetune.FineTuner, error) {
+	providerCfg := globalConfig.Providers["openai"]
+	apiKey := providerCfg.APIKey
+	if envKey := os.Getenv("OPENAI_API_KEY"); envKey != "" {
+		apiKey = envKey
+	}
+	return finetune.NewOpenAIFineTuner(apiKey, providerCfg.BaseURL)
+}
+
+func runFinetuneCreate(tuner finetune.FineTuner, cfg *config.FileConfig, globalConfig *config.GlobalConfig, args []string) error {
+	fs := flag.NewFlagSet("finetune create", flag.ExitOnError)
+	trainingFile := fs.String("file", "", "path to the JSONL training file (required)")
+	validationFile := fs.String("validation-file", "", "path to a JSONL validation file")
+	model := fs.String("model", "gpt-3.5-turbo", "base model to fine-tune")
+	suffix := fs.String("suffix", "", "suffix appended to the fine-tuned model's name")
+	nEpochs := fs.Int("n-epochs", 0, "number of training epochs (0: provider default)")
+	batchSize := fs.Int("batch-size", 0, "training batch size (0: provider default)")
+	lrMultiplier := fs.Float64("lr-multiplier", 0, "learning rate multiplier (0: provider default)")
+	wait := fs.Bool("wait", false, "tail events and register the resulting model as the default once training finishes")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *trainingFile == "" {
+		return errors.New("-file is required")
+	}
+
+	ctx := context.Background()
+
+	trainingFileID, err := uploadFile(ctx, tuner, *trainingFile)
+	if err != nil {
+		return fmt.Errorf("uploading training file: %w", err)
+	}
+	fmt.Printf("Uploaded training file: %s\n", trainingFileID)
+
+	var validationFileID string
+	if *validationFile != "" {
+		validationFileID, err = uploadFile(ctx, tuner, *validationFile)
+		if err != nil {
+			return fmt.Errorf("uploading validation file: %w", err)
+		}
+		fmt.Printf("Uploaded validation file: %s\n", validationFileID)
+	}
+
+	job, err := tuner.CreateJob(ctx, finetune.JobRequest{
+		TrainingFile:   trainingFileID,
+		ValidationFile: validationFileID,
+		Model:          *model,
+		Suffix:         *suffix,
+		Hyperparameters: finetune.Hyperparameters{
+			NEpochs:                *nEpochs,
+			BatchSize:              *batchSize,
+			LearningRateMultiplier: *lrMultiplier,
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("creating job: %w", err)
+	}
+	fmt.Printf("Created job %s (status: %s)\n", job.ID, job.Status)
+
+	if !*wait {
+		return nil
+	}
+
+	job, err = tailFinetuneEvents(ctx, tuner, job.ID)
+	if err != nil {
+		return err
+	}
+
+	if job.Status != "succeeded" || job.FineTunedModel == "" {
+		return fmt.Errorf("job %s finished with status %q", job.ID, job.Status)
+	}
+
+	return registerFineTunedModel(cfg, globalConfig, job.FineTunedModel)
+}
+
+func runFinetuneList(tuner finetune.FineTuner, args []string) error {
+	fs := flag.NewFlagSet("finetune list", flag.ExitOnError)
+	limit := fs.Int("limit", 0, "maximum number of jobs to return")
+	after := fs.String("after", "", "paginate starting after this job id")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	jobs, err := tuner.List(context.Background(), finetune.ListOpts{Limit: *limit, After: *after})
+	if err != nil {
+		return err
+	}
+
+	for _, job := range jobs {
+		fmt.Printf("%s\t%s\t%s\n", job.ID, job.Status, job.FineTunedModel)
+	}
+	return nil
+}
+
+func runFinetuneRetrieve(tuner finetune.FineTuner, args []string) error {
+	if len(args) == 0 {
+		return errors.New("usage: kode finetune retrieve <job-id>")
+	}
+
+	job, err := tuner.Retrieve(context.Background(), args[0])
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%s\t%s\t%s\n", job.ID, job.Status, job.FineTunedModel)
+	return nil
+}
+
+func runFinetuneCancel(tuner finetune.FineTuner, args []string) error {
+	if len(args) == 0 {
+		return errors.New("usage: kode finetune cancel <job-id>")
+	}
+
+	job, err := tuner.Cancel(context.Background(), args[0])
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%s\t%s\n", job.ID, job.Status)
+	return nil
+}
+
+func runFinetuneEvents(tuner finetune.FineTuner, args []string) error {
+	if len(args) == 0 {
+		return errors.New("usage: kode finetune events <job-id>")
+	}
+
+	_, err := tailFinetuneEvents(context.Background(), tuner, args[0])
+	return err
+}
+
+// tailFinetuneEvents prints job id's events as they arrive and returns its
+// final state once the event stream closes.
+func tailFinetuneEvents(ctx context.Context, tuner finetune.FineTuner, id string) (*finetune.Job, error) {
+	events, err := tuner.StreamEvents(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	for event := range events {
+		fmt.Printf("[%s] %s\n", event.Level, event.Message)
+	}
+	return tuner.Retrieve(ctx, id)
+}
+
+func uploadFile(ctx context.Context, tuner finetune.FineTuner, path string) (string, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return tuner.UploadFile(ctx, filepath.Base(path), content)
+}
+
+// registerFineTunedModel saves modelID as the new default model and
+// persists it to the global config.
+func registerFineTunedModel(cfg *config.FileConfig, globalConfig *config.GlobalConfig, modelID string) error {
+	globalConfig.DefaultModel = "openai:" + modelID
+	if err := cfg.SaveGlobalConfig(globalConfig); err != nil {
+		return fmt.Errorf("saving config: %w", err)
+	}
+	fmt.Printf("Registered %s as the default model\n", globalConfig.DefaultModel)
+	return nil
+}
@@ -2,15 +2,18 @@ package main
 
 import (
 	"context"
+	"flag"
 	"fmt"
 	"log"
 	"os"
 	"path/filepath"
 
 	"github.com/charmbracelet/bubbletea"
+	"github.com/cinience/aigo-kode/internal/agents"
 	"github.com/cinience/aigo-kode/internal/ai"
 	"github.com/cinience/aigo-kode/internal/config"
 	"github.com/cinience/aigo-kode/internal/core"
+	"github.com/cinience/aigo-kode/internal/sandbox"
 	"github.com/cinience/aigo-kode/internal/tools"
 )
 
@@ -42,6 +45,16 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				return m, nil
 			}
 
+			if m.input == "/compact" {
+				m.input = ""
+				if err := m.session.Compact(context.Background()); err != nil {
+					m.messages = append(m.messages, fmt.Sprintf("Compact failed: %v", err))
+				} else {
+					m.messages = append(m.messages, "History compacted.")
+				}
+				return m, nil
+			}
+
 			// Add user message
 			m.session.AddUserMessage(m.input)
 			m.messages = append(m.messages, fmt.Sprintf("User: %s", m.input))
@@ -141,6 +154,29 @@ func (m Model) View() string {
 // userMessageMsg represents a user message
 type userMessageMsg string
 
+// aiProviders converts the saved provider configs into the shape
+// ai.NewModel expects, attaching presets to the openai provider if a
+// model registry is given.
+func aiProviders(globalConfig *config.GlobalConfig, presets *config.ModelRegistry) map[string]ai.ProviderConfig {
+	providers := make(map[string]ai.ProviderConfig, len(globalConfig.Providers))
+	for name, p := range globalConfig.Providers {
+		providers[name] = ai.ProviderConfig{
+			APIKey:          p.APIKey,
+			BaseURL:         p.BaseURL,
+			DefaultModel:    p.DefaultModel,
+			Headers:         p.Headers,
+			SecretAccessKey: p.SecretAccessKey,
+			Region:          p.Region,
+		}
+	}
+	if presets != nil {
+		openaiCfg := providers["openai"]
+		openaiCfg.Presets = presets
+		providers["openai"] = openaiCfg
+	}
+	return providers
+}
+
 func main() {
 	// Set up config
 	homeDir, err := os.UserHomeDir()
@@ -160,31 +196,128 @@ func main() {
 		log.Fatalf("Failed to get global config: %v", err)
 	}
 
-	// Set up OpenAI model
-	apiKey := os.Getenv("OPENAI_API_KEY")
-	if apiKey == "" {
-		// Try to get from config
-		apiKey = globalConfig.APIKeys["openai"]
-		if apiKey == "" {
-			log.Fatalf("OpenAI API key not found. Set OPENAI_API_KEY environment variable or configure in settings.")
+	if len(os.Args) > 1 && os.Args[1] == "finetune" {
+		if err := runFinetuneCommand(os.Args[2:], cfg, globalConfig); err != nil {
+			log.Fatalf("finetune: %v", err)
+		}
+		return
+	}
+
+	if len(os.Args) > 1 && os.Args[1] == "serve" {
+		if err := runServeCommand(os.Args[2:], configDir, globalConfig); err != nil {
+			log.Fatalf("serve: %v", err)
 		}
+		return
+	}
+
+	agentName := flag.String("agent", "", "name of the agent profile to use (see ~/.go-anon-kode/agents)")
+	flag.StringVar(agentName, "a", "", "shorthand for -agent")
+	skipPermissions := flag.Bool("dangerously-skip-permissions", false, "allow every tool call without prompting (use only in a sandboxed environment)")
+	flag.Parse()
+
+	agentRegistry, err := agents.NewRegistry(filepath.Join(configDir, "agents"))
+	if err != nil {
+		log.Fatalf("Failed to load agent profiles: %v", err)
+	}
+
+	var activeAgent *agents.Agent
+	if *agentName != "" {
+		agent, ok := agentRegistry.Get(*agentName)
+		if !ok {
+			log.Fatalf("unknown agent %q", *agentName)
+		}
+		activeAgent = agent
+	}
+
+	// Load model presets from ~/.go-anon-kode/models, if any
+	modelRegistry, err := config.NewModelRegistry(filepath.Join(configDir, "models"))
+	if err != nil {
+		log.Fatalf("Failed to load model presets: %v", err)
+	}
+
+	// Set up the AI model from the configured "provider:model" selection,
+	// or the agent's pinned model if one is active
+	modelIdentifier := globalConfig.DefaultModel
+	if activeAgent != nil && activeAgent.Model != "" {
+		modelIdentifier = activeAgent.Model
 	}
 
-	model, err := ai.NewOpenAIModel(apiKey, globalConfig.DefaultModel)
+	providers := aiProviders(globalConfig, modelRegistry)
+	if apiKey := os.Getenv("OPENAI_API_KEY"); apiKey != "" {
+		openaiCfg := providers["openai"]
+		openaiCfg.APIKey = apiKey
+		providers["openai"] = openaiCfg
+	}
+
+	model, err := ai.NewModel(modelIdentifier, providers)
 	if err != nil {
-		log.Fatalf("Failed to create OpenAI model: %v", err)
+		log.Fatalf("Failed to create AI model: %v", err)
 	}
 
-	// Set up tool registry
-	registry := tools.DefaultToolRegistry()
+	// Set up tool registry, scoped to the active agent's allowed tools if
+	// any, and bound to the project's Bash policy if it sets one
+	projectConfig, err := cfg.GetProjectConfig(".")
+	if err != nil {
+		log.Fatalf("Failed to load project config: %v", err)
+	}
+
+	var registry *tools.ToolRegistry
+	if projectConfig.BashPolicy != nil {
+		registry = tools.DefaultToolRegistryWithBashPolicy(projectConfig.BashPolicy, sandbox.ResourceLimits{})
+	} else {
+		registry = tools.DefaultToolRegistry()
+	}
+	registry.RegisterMCPServers(globalConfig.MCPServers)
+
+	sessionTools := registry.GetAllTools()
+	systemPrompt := "You are a helpful AI coding assistant. You can help with coding tasks, answer questions, and use tools to interact with the file system."
+	if activeAgent != nil {
+		sessionTools = registry.GetTools(activeAgent.AllowedTools)
+		if activeAgent.SystemPrompt != "" {
+			systemPrompt = activeAgent.SystemPrompt
+		}
+	}
 
 	// Create session
-	session := core.NewSession(model, registry.GetAllTools(), &core.SessionConfig{
-		ProjectPath:  ".",
-		SystemPrompt: "You are a helpful AI coding assistant. You can help with coding tasks, answer questions, and use tools to interact with the file system.",
-		MaxTokens:    4096,
-		Temperature:  0.7,
+	session := core.NewSession(model, sessionTools, &core.SessionConfig{
+		ProjectPath:     ".",
+		SystemPrompt:    systemPrompt,
+		MaxTokens:       4096,
+		Temperature:     0.7,
+		BashPolicy:      projectConfig.BashPolicy,
+		SkipPermissions: *skipPermissions,
 	})
+	session.PermissionBroker = NewStdinPermissionBroker()
+	session.PermissionPolicy = &core.PathPolicy{Root: "."}
+	for toolName, fingerprints := range projectConfig.ApprovedTools {
+		session.Approvals[toolName] = make(map[string]bool, len(fingerprints))
+		for _, fp := range fingerprints {
+			session.Approvals[toolName][fp] = true
+		}
+	}
+	session.OnApprovalGranted = func(toolName, fingerprint string) {
+		if projectConfig.ApprovedTools == nil {
+			projectConfig.ApprovedTools = make(map[string][]string)
+		}
+		projectConfig.ApprovedTools[toolName] = append(projectConfig.ApprovedTools[toolName], fingerprint)
+		if err := cfg.SaveProjectConfig(".", projectConfig); err != nil {
+			log.Printf("Failed to persist tool approval: %v", err)
+		}
+	}
+
+	// Pin any files the agent wants permanently in context
+	if activeAgent != nil {
+		for _, path := range activeAgent.ContextFiles {
+			content, err := os.ReadFile(path)
+			if err != nil {
+				log.Fatalf("reading pinned context file %q: %v", path, err)
+			}
+			session.Messages = append(session.Messages, core.Message{
+				Role:    "system",
+				Content: fmt.Sprintf("Pinned context from %s:\n%s", path, content),
+			})
+		}
+	}
 
 	// Create and run the Bubble Tea application
 	p := tea.NewProgram(Model{
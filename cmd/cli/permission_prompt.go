@@ -0,0 +1,56 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/cinience/aigo-kode/internal/core"
+)
+
+// StdinPermissionBroker prompts the user on the terminal for each tool call
+// that requires permission. It reads from os.Stdin directly rather than
+// going through the Bubble Tea program, since Update already calls
+// ExecuteTool synchronously and blocking there for a keypress is no
+// different from blocking on any other tool's Execute call.
+type StdinPermissionBroker struct {
+	reader *bufio.Reader
+}
+
+// NewStdinPermissionBroker creates a StdinPermissionBroker reading from
+// os.Stdin.
+func NewStdinPermissionBroker() *StdinPermissionBroker {
+	return &StdinPermissionBroker{reader: bufio.NewReader(os.Stdin)}
+}
+
+// Request prompts "Allow <tool> (<fingerprint>) to run? [y/N/always]" and
+// blocks for an answer, returning PermissionDenied if ctx is done first.
+func (b *StdinPermissionBroker) Request(ctx context.Context, req core.PermissionRequest) (core.PermissionDecision, error) {
+	answers := make(chan string, 1)
+	go func() {
+		what := req.ToolName
+		if req.Fingerprint != "" {
+			what = fmt.Sprintf("%s (%s)", req.ToolName, req.Fingerprint)
+		}
+		fmt.Printf("\nAllow %s to run? [y/N/always] ", what)
+
+		line, _ := b.reader.ReadString('\n')
+		answers <- strings.ToLower(strings.TrimSpace(line))
+	}()
+
+	select {
+	case <-ctx.Done():
+		return core.PermissionDenied, ctx.Err()
+	case answer := <-answers:
+		switch answer {
+		case "y", "yes":
+			return core.PermissionAllowedOnce, nil
+		case "a", "always":
+			return core.PermissionAllowedAlways, nil
+		default:
+			return core.PermissionDenied, nil
+		}
+	}
+}
@@ -0,0 +1,417 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/cinience/aigo-kode/internal/ai"
+	"github.com/cinience/aigo-kode/internal/config"
+	"github.com/cinience/aigo-kode/internal/core"
+	"github.com/cinience/aigo-kode/internal/tools"
+	"github.com/gin-gonic/gin"
+	"github.com/sashabaranov/go-openai"
+)
+
+// openAIServer exposes kode's AIModel + tool-execution loop through an
+// OpenAI-compatible HTTP surface, so existing OpenAI SDKs and UIs (e.g.
+// chatbot-ui) can drive it as their backend.
+type openAIServer struct {
+	globalConfig  *config.GlobalConfig
+	toolRegistry  *tools.ToolRegistry
+	modelRegistry *config.ModelRegistry
+	// skipPermissions allows every tool call without prompting, since this
+	// headless surface has no terminal to prompt on. Set from
+	// --dangerously-skip-permissions.
+	skipPermissions bool
+}
+
+// runServeCommand starts `kode serve`, an HTTP server implementing the
+// OpenAI-compatible chat/completions, completions, embeddings, and models
+// endpoints on top of kode's own model and tool abstractions.
+func runServeCommand(args []string, configDir string, globalConfig *config.GlobalConfig) error {
+	fs := flag.NewFlagSet("serve", flag.ExitOnError)
+	addr := fs.String("addr", ":8081", "address to listen on")
+	skipPermissions := fs.Bool("dangerously-skip-permissions", false, "allow every tool call without prompting (there's no terminal to prompt on here anyway)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	modelRegistry, err := config.NewModelRegistry(filepath.Join(configDir, "models"))
+	if err != nil {
+		return err
+	}
+
+	srv := &openAIServer{
+		globalConfig:    globalConfig,
+		toolRegistry:    tools.DefaultToolRegistry(),
+		modelRegistry:   modelRegistry,
+		skipPermissions: *skipPermissions,
+	}
+
+	fmt.Printf("kode serve: listening on %s\n", *addr)
+	return srv.router().Run(*addr)
+}
+
+// router builds the gin.Engine implementing the OpenAI-compatible surface.
+func (s *openAIServer) router() *gin.Engine {
+	router := gin.Default()
+	v1 := router.Group("/v1")
+	{
+		v1.GET("/models", s.handleListModels)
+		v1.POST("/chat/completions", s.handleChatCompletions)
+		v1.POST("/completions", s.handleCompletions)
+		v1.POST("/embeddings", s.handleEmbeddings)
+	}
+	return router
+}
+
+// providers converts s.globalConfig into the shape ai.NewModel expects,
+// attaching presets to the openai provider and honoring the same
+// OPENAI_API_KEY env override used elsewhere.
+func (s *openAIServer) providers() map[string]ai.ProviderConfig {
+	providers := make(map[string]ai.ProviderConfig, len(s.globalConfig.Providers))
+	for name, p := range s.globalConfig.Providers {
+		providers[name] = ai.ProviderConfig{
+			APIKey:          p.APIKey,
+			BaseURL:         p.BaseURL,
+			DefaultModel:    p.DefaultModel,
+			Headers:         p.Headers,
+			SecretAccessKey: p.SecretAccessKey,
+			Region:          p.Region,
+		}
+	}
+	if s.modelRegistry != nil {
+		openaiCfg := providers["openai"]
+		openaiCfg.Presets = s.modelRegistry
+		providers["openai"] = openaiCfg
+	}
+	if apiKey := os.Getenv("OPENAI_API_KEY"); apiKey != "" {
+		openaiCfg := providers["openai"]
+		openaiCfg.APIKey = apiKey
+		providers["openai"] = openaiCfg
+	}
+	return providers
+}
+
+// chatMessage is one entry of an OpenAI chat/completions messages array.
+type chatMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+// chatCompletionRequest is the subset of OpenAI's chat/completions request
+// body kode understands. Tools, if present, are matched against kode's
+// registered tools by name, so only the tools the caller actually declared
+// are made available to the model and eligible for server-side execution.
+type chatCompletionRequest struct {
+	Model    string        `json:"model"`
+	Messages []chatMessage `json:"messages"`
+	Stream   bool          `json:"stream"`
+	Tools    []struct {
+		Function struct {
+			Name string `json:"name"`
+		} `json:"function"`
+	} `json:"tools,omitempty"`
+}
+
+// buildSession translates req into a core.Session, passing req.Messages
+// through verbatim (including any system message the caller sent) rather
+// than going through core.NewSession's single-system-prompt shape.
+func (s *openAIServer) buildSession(req chatCompletionRequest) (*core.Session, error) {
+	modelIdentifier := req.Model
+	if modelIdentifier == "" {
+		modelIdentifier = s.globalConfig.DefaultModel
+	}
+
+	model, err := ai.NewModel(modelIdentifier, s.providers())
+	if err != nil {
+		return nil, err
+	}
+
+	var sessionTools []core.Tool
+	if len(req.Tools) > 0 {
+		names := make([]string, len(req.Tools))
+		for i, t := range req.Tools {
+			names[i] = t.Function.Name
+		}
+		sessionTools = s.toolRegistry.GetTools(names)
+	}
+
+	messages := make([]core.Message, len(req.Messages))
+	for i, m := range req.Messages {
+		messages[i] = core.Message{Role: m.Role, Content: m.Content}
+	}
+
+	return &core.Session{
+		Messages: messages,
+		Model:    model,
+		Tools:    sessionTools,
+		Config: &core.SessionConfig{
+			MaxTokens:       4096,
+			Temperature:     0.7,
+			SkipPermissions: s.skipPermissions,
+		},
+	}, nil
+}
+
+// handleChatCompletions implements POST /v1/chat/completions. Every turn -
+// streaming or not - goes through Session.RunTurn, so tool calls the model
+// requests are actually executed server-side before the response (or final
+// stream chunk) is returned to the caller.
+func (s *openAIServer) handleChatCompletions(c *gin.Context) {
+	var req chatCompletionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": gin.H{"message": err.Error()}})
+		return
+	}
+
+	session, err := s.buildSession(req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": gin.H{"message": err.Error()}})
+		return
+	}
+
+	if req.Stream {
+		s.streamChatCompletion(c, session, req.Model)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 60*time.Second)
+	defer cancel()
+
+	content, err := runTurnCollecting(ctx, session)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": gin.H{"message": err.Error()}})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"id":      fmt.Sprintf("chatcmpl-%d", time.Now().UnixNano()),
+		"object":  "chat.completion",
+		"created": time.Now().Unix(),
+		"model":   req.Model,
+		"choices": []gin.H{
+			{
+				"index":         0,
+				"message":       gin.H{"role": "assistant", "content": content},
+				"finish_reason": "stop",
+			},
+		},
+	})
+}
+
+// streamChatCompletion drives session's turn and forwards each token as an
+// OpenAI-style chat.completion.chunk SSE event, ending with "data: [DONE]".
+func (s *openAIServer) streamChatCompletion(c *gin.Context, session *core.Session, model string) {
+	flusher, ok := c.Writer.(http.Flusher)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": gin.H{"message": "streaming unsupported"}})
+		return
+	}
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Writer.WriteHeader(http.StatusOK)
+
+	id := fmt.Sprintf("chatcmpl-%d", time.Now().UnixNano())
+	writeChunk := func(delta gin.H, finishReason interface{}) {
+		fmt.Fprintf(c.Writer, "data: %s\n\n", toJSON(gin.H{
+			"id":      id,
+			"object":  "chat.completion.chunk",
+			"created": time.Now().Unix(),
+			"model":   model,
+			"choices": []gin.H{
+				{"index": 0, "delta": delta, "finish_reason": finishReason},
+			},
+		}))
+		flusher.Flush()
+	}
+
+	events := make(chan core.TurnEvent)
+	go session.RunTurn(c.Request.Context(), events)
+
+	for evt := range events {
+		switch evt.Type {
+		case core.EventToken:
+			writeChunk(gin.H{"content": evt.Content}, nil)
+		case core.EventDone:
+			if evt.Err != nil {
+				writeChunk(gin.H{}, "error")
+			} else {
+				writeChunk(gin.H{}, "stop")
+			}
+		}
+	}
+
+	fmt.Fprint(c.Writer, "data: [DONE]\n\n")
+	flusher.Flush()
+}
+
+// runTurnCollecting drives one full turn and returns the assistant's final
+// text, executing any tool calls the model requests along the way.
+func runTurnCollecting(ctx context.Context, session *core.Session) (string, error) {
+	events := make(chan core.TurnEvent)
+	go session.RunTurn(ctx, events)
+
+	var content string
+	var turnErr error
+	for evt := range events {
+		switch evt.Type {
+		case core.EventToken:
+			content += evt.Content
+		case core.EventDone:
+			turnErr = evt.Err
+		}
+	}
+	return content, turnErr
+}
+
+// handleCompletions implements POST /v1/completions by wrapping the prompt
+// in a single user message and running it through the same turn loop.
+func (s *openAIServer) handleCompletions(c *gin.Context) {
+	var req struct {
+		Model  string `json:"model"`
+		Prompt string `json:"prompt"`
+		Stream bool   `json:"stream"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": gin.H{"message": err.Error()}})
+		return
+	}
+
+	session, err := s.buildSession(chatCompletionRequest{
+		Model:    req.Model,
+		Messages: []chatMessage{{Role: "user", Content: req.Prompt}},
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": gin.H{"message": err.Error()}})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 60*time.Second)
+	defer cancel()
+
+	content, err := runTurnCollecting(ctx, session)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": gin.H{"message": err.Error()}})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"id":      fmt.Sprintf("cmpl-%d", time.Now().UnixNano()),
+		"object":  "text_completion",
+		"created": time.Now().Unix(),
+		"model":   req.Model,
+		"choices": []gin.H{
+			{"index": 0, "text": content, "finish_reason": "stop"},
+		},
+	})
+}
+
+// handleEmbeddings implements POST /v1/embeddings. core.AIModel has no
+// embeddings concept, so this talks to the OpenAI embeddings API directly
+// using the configured openai provider credentials rather than going
+// through the AIModel abstraction.
+func (s *openAIServer) handleEmbeddings(c *gin.Context) {
+	var req struct {
+		Model string   `json:"model"`
+		Input []string `json:"input"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": gin.H{"message": err.Error()}})
+		return
+	}
+
+	providerCfg := s.globalConfig.Providers["openai"]
+	apiKey := providerCfg.APIKey
+	if envKey := os.Getenv("OPENAI_API_KEY"); envKey != "" {
+		apiKey = envKey
+	}
+	if apiKey == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": gin.H{"message": "no openai API key configured"}})
+		return
+	}
+
+	clientCfg := openai.DefaultConfig(apiKey)
+	if providerCfg.BaseURL != "" {
+		clientCfg.BaseURL = providerCfg.BaseURL
+	}
+	client := openai.NewClientWithConfig(clientCfg)
+
+	model := req.Model
+	if model == "" {
+		model = string(openai.AdaEmbeddingV2)
+	}
+
+	resp, err := client.CreateEmbeddings(c.Request.Context(), openai.EmbeddingRequest{
+		Input: req.Input,
+		Model: openai.EmbeddingModel(model),
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": gin.H{"message": err.Error()}})
+		return
+	}
+
+	data := make([]gin.H, len(resp.Data))
+	for i, d := range resp.Data {
+		data[i] = gin.H{"object": "embedding", "embedding": d.Embedding, "index": d.Index}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"object": "list",
+		"data":   data,
+		"model":  string(resp.Model),
+		"usage": gin.H{
+			"prompt_tokens": resp.Usage.PromptTokens,
+			"total_tokens":  resp.Usage.TotalTokens,
+		},
+	})
+}
+
+// handleListModels implements GET /v1/models, enumerating the
+// "provider:model" aliases kode has configured plus any YAML model
+// presets.
+func (s *openAIServer) handleListModels(c *gin.Context) {
+	seen := make(map[string]bool)
+	var data []gin.H
+
+	add := func(id string) {
+		if id == "" || seen[id] {
+			return
+		}
+		seen[id] = true
+		data = append(data, gin.H{"id": id, "object": "model", "owned_by": "kode"})
+	}
+
+	add(s.globalConfig.DefaultModel)
+	for name, p := range s.globalConfig.Providers {
+		if p.DefaultModel != "" {
+			add(name + ":" + p.DefaultModel)
+		}
+	}
+	if s.modelRegistry != nil {
+		for _, name := range s.modelRegistry.Names() {
+			add(name)
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"object": "list", "data": data})
+}
+
+// toJSON marshals v to a compact JSON string for use as SSE event data,
+// falling back to an empty object if marshaling fails so a bad value never
+// corrupts the stream framing.
+func toJSON(v interface{}) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "{}"
+	}
+	return string(data)
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/cinience/aigo-kode/internal/config"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandleListModels(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	srv := &openAIServer{
+		globalConfig: &config.GlobalConfig{
+			DefaultModel: "openai:gpt-3.5-turbo",
+			Providers: map[string]config.ProviderConfig{
+				"openai":    {DefaultModel: "gpt-3.5-turbo"},
+				"anthropic": {DefaultModel: "claude-3-5-sonnet-latest"},
+			},
+		},
+		toolRegistry: nil,
+	}
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/v1/models", nil)
+	srv.router().ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var resp struct {
+		Object string `json:"object"`
+		Data   []struct {
+			ID string `json:"id"`
+		} `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, "list", resp.Object)
+
+	var ids []string
+	for _, d := range resp.Data {
+		ids = append(ids, d.ID)
+	}
+	assert.Contains(t, ids, "openai:gpt-3.5-turbo")
+	assert.Contains(t, ids, "anthropic:claude-3-5-sonnet-latest")
+}
+
+func TestProvidersAttachesPresets(t *testing.T) {
+	dir := t.TempDir()
+	modelRegistry, err := config.NewModelRegistry(dir)
+	assert.NoError(t, err)
+
+	srv := &openAIServer{
+		globalConfig: &config.GlobalConfig{
+			Providers: map[string]config.ProviderConfig{"openai": {APIKey: "test-key"}},
+		},
+		modelRegistry: modelRegistry,
+	}
+
+	providers := srv.providers()
+	assert.Equal(t, modelRegistry, providers["openai"].Presets)
+}
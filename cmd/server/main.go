@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"os"
 	"path/filepath"
+	"strconv"
 	"time"
 
 	"github.com/cinience/aigo-kode/internal/ai"
@@ -17,39 +18,12 @@ import (
 
 // Server represents the web server
 type Server struct {
-	router       *gin.Engine
-	toolRegistry *tools.ToolRegistry
-	config       *config.FileConfig
-}
-
-// SessionStore manages active sessions
-type SessionStore struct {
-	sessions map[string]*core.Session
-}
-
-// NewSessionStore creates a new session store
-func NewSessionStore() *SessionStore {
-	return &SessionStore{
-		sessions: make(map[string]*core.Session),
-	}
-}
-
-// GetSession retrieves a session by ID, creating it if it doesn't exist
-func (s *SessionStore) GetSession(id string, model core.AIModel, tools []core.Tool) *core.Session {
-	if session, ok := s.sessions[id]; ok {
-		return session
-	}
-
-	// Create new session
-	session := core.NewSession(model, tools, &core.SessionConfig{
-		ProjectPath:  ".",
-		SystemPrompt: "You are a helpful AI coding assistant. You can help with coding tasks, answer questions, and use tools to interact with the file system.",
-		MaxTokens:    4096,
-		Temperature:  0.7,
-	})
-
-	s.sessions[id] = session
-	return session
+	router           *gin.Engine
+	toolRegistry     *tools.ToolRegistry
+	config           *config.FileConfig
+	sessionStore     *SessionStore
+	modelRegistry    *config.ModelRegistry
+	permissionBroker *HTTPPermissionBroker
 }
 
 // NewServer creates a new web server
@@ -72,19 +46,42 @@ func NewServer() (*Server, error) {
 	// Create router
 	router := gin.Default()
 
+	// Set up persistent session storage under ~/.go-anon-kode/sessions
+	backend, err := NewJSONFileBackend(filepath.Join(configDir, "sessions"))
+	if err != nil {
+		return nil, err
+	}
+
+	// Load model presets from ~/.go-anon-kode/models, if any
+	modelRegistry, err := config.NewModelRegistry(filepath.Join(configDir, "models"))
+	if err != nil {
+		return nil, err
+	}
+
 	// Create server
 	server := &Server{
-		router:       router,
-		toolRegistry: registry,
-		config:       cfg,
+		router:           router,
+		toolRegistry:     registry,
+		config:           cfg,
+		sessionStore:     NewSessionStore(backend),
+		modelRegistry:    modelRegistry,
+		permissionBroker: NewHTTPPermissionBroker(),
 	}
 
 	// Set up routes
 	server.setupRoutes()
 
+	server.sessionStore.StartEvictionLoop()
+
 	return server, nil
 }
 
+// Close stops the server's background eviction loop. Call it once the
+// server is no longer serving requests.
+func (s *Server) Close() {
+	s.sessionStore.Stop()
+}
+
 // setupRoutes configures the API routes
 func (s *Server) setupRoutes() {
 	// Serve static files for the web UI
@@ -96,13 +93,19 @@ func (s *Server) setupRoutes() {
 	api := s.router.Group("/api")
 	{
 		api.POST("/chat", s.handleChat)
+		api.GET("/chat/stream", s.handleChatStream)
 		api.GET("/chat/history", s.handleGetChatHistory)
+		api.GET("/sessions", s.handleListSessions)
+		api.GET("/sessions/:id", s.handleGetSession)
+		api.DELETE("/sessions/:id", s.handleDeleteSession)
 		api.POST("/tools/:toolName", s.handleExecuteTool)
 		api.GET("/files", s.handleListFiles)
+		api.GET("/browse", s.handleBrowseFiles)
 		api.GET("/files/:path", s.handleGetFile)
 		api.PUT("/files/:path", s.handleUpdateFile)
 		api.GET("/config", s.handleGetConfig)
 		api.PUT("/config", s.handleUpdateConfig)
+		api.POST("/permissions/:token", s.handleResolvePermission)
 	}
 }
 
@@ -124,13 +127,24 @@ type ChatResponse struct {
 	ToolCalls []core.ToolCall `json:"toolCalls,omitempty"`
 }
 
-// handleChat handles chat API requests
+// handleChat handles chat API requests. If the client sends
+// Accept: text/event-stream it upgrades to the same SSE event stream served
+// by GET /api/chat/stream instead of returning a single blocking response.
 func (s *Server) handleChat(c *gin.Context) {
+	if c.GetHeader("Accept") == "text/event-stream" {
+		s.handleChatStream(c)
+		return
+	}
+
 	var req ChatRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	if err := validateSessionID(req.SessionID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 
 	// Get global config
 	globalConfig, err := s.config.GetGlobalConfig()
@@ -139,26 +153,23 @@ func (s *Server) handleChat(c *gin.Context) {
 		return
 	}
 
-	// Set up OpenAI model
-	apiKey := os.Getenv("OPENAI_API_KEY")
-	if apiKey == "" {
-		// Try to get from config
-		apiKey = globalConfig.APIKeys["openai"]
-		if apiKey == "" {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "OpenAI API key not found"})
-			return
-		}
+	// Set up the AI model from the configured "provider:model" selection
+	providers := aiProviders(globalConfig, s.modelRegistry)
+	if apiKey := os.Getenv("OPENAI_API_KEY"); apiKey != "" {
+		openaiCfg := providers["openai"]
+		openaiCfg.APIKey = apiKey
+		providers["openai"] = openaiCfg
 	}
 
-	model, err := ai.NewOpenAIModel(apiKey, globalConfig.DefaultModel, globalConfig.BaseURL)
+	model, err := ai.NewModel(globalConfig.DefaultModel, providers)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create model"})
 		return
 	}
 
 	// Get or create session
-	sessionStore := NewSessionStore()
-	session := sessionStore.GetSession(req.SessionID, model, s.toolRegistry.GetAllTools())
+	session := s.sessionStore.GetSession(req.SessionID, model, s.toolRegistry.GetAllTools())
+	session.PermissionBroker = s.permissionBroker
 
 	// Add user message
 	session.AddUserMessage(req.Message)
@@ -200,6 +211,10 @@ func (s *Server) handleChat(c *gin.Context) {
 	// Add assistant message
 	session.AddAssistantMessage(resp.Content)
 
+	if err := s.sessionStore.Save(req.SessionID); err != nil {
+		log.Printf("Failed to persist session %s: %v", req.SessionID, err)
+	}
+
 	// Return response
 	c.JSON(http.StatusOK, ChatResponse{
 		Response:  resp.Content,
@@ -207,10 +222,65 @@ func (s *Server) handleChat(c *gin.Context) {
 	})
 }
 
-// handleGetChatHistory handles requests to get chat history
+// handleGetChatHistory handles requests to get chat history for the session
+// identified by the sessionId query parameter.
 func (s *Server) handleGetChatHistory(c *gin.Context) {
-	// In a real implementation, this would retrieve the chat history from the session
-	c.JSON(http.StatusOK, gin.H{"messages": []Message{}})
+	sessionID := c.Query("sessionId")
+	if sessionID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "sessionId is required"})
+		return
+	}
+	if err := validateSessionID(sessionID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	messages, err := s.sessionStore.Messages(sessionID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "session not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"messages": messages})
+}
+
+// handleListSessions returns the ids of every known session.
+func (s *Server) handleListSessions(c *gin.Context) {
+	ids, err := s.sessionStore.List()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"sessions": ids})
+}
+
+// handleGetSession returns the message history for a single session.
+func (s *Server) handleGetSession(c *gin.Context) {
+	id := c.Param("id")
+	if err := validateSessionID(id); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	messages, err := s.sessionStore.Messages(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "session not found"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"id": id, "messages": messages})
+}
+
+// handleDeleteSession removes a session from memory and persistent storage.
+func (s *Server) handleDeleteSession(c *gin.Context) {
+	id := c.Param("id")
+	if err := validateSessionID(id); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := s.sessionStore.Delete(id); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true})
 }
 
 // ToolRequest represents a tool execution request
@@ -279,6 +349,59 @@ func (s *Server) handleListFiles(c *gin.Context) {
 	c.JSON(http.StatusOK, output)
 }
 
+// handleBrowseFiles handles requests to browse a directory with pagination,
+// sorting, filtering, and optional recursive listing, suitable for driving
+// a file browser UI directly from one response.
+func (s *Server) handleBrowseFiles(c *gin.Context) {
+	lsTool := s.toolRegistry.GetTool("LS")
+	if lsTool == nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "LS tool not found"})
+		return
+	}
+
+	path := c.Query("path")
+	if path == "" {
+		path = "."
+	}
+
+	input := map[string]interface{}{"path": path}
+	if v := c.Query("sort_by"); v != "" {
+		input["sort_by"] = v
+	}
+	if v := c.Query("order"); v != "" {
+		input["order"] = v
+	}
+	if v := c.Query("filter"); v != "" {
+		input["filter"] = v
+	}
+	if v := c.Query("show_hidden"); v != "" {
+		input["show_hidden"] = v == "true"
+	}
+	for _, key := range []string{"depth", "limit", "offset"} {
+		if v := c.Query(key); v != "" {
+			n, err := strconv.Atoi(v)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": key + " must be a number"})
+				return
+			}
+			input[key] = float64(n)
+		}
+	}
+
+	if err := lsTool.ValidateInput(input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	output, err := lsTool.Execute(c.Request.Context(), input)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, output)
+}
+
 // handleGetFile handles requests to get file content
 func (s *Server) handleGetFile(c *gin.Context) {
 	path := c.Param("path")
@@ -354,13 +477,16 @@ func (s *Server) handleGetConfig(c *gin.Context) {
 	}
 
 	// Don't expose API keys directly
+	hasAPIKeys := make(map[string]bool, len(globalConfig.Providers))
+	for name, p := range globalConfig.Providers {
+		hasAPIKeys[name] = p.APIKey != ""
+	}
+
 	safeConfig := map[string]interface{}{
 		"defaultModel":           globalConfig.DefaultModel,
 		"hasCompletedOnboarding": globalConfig.HasCompletedOnboarding,
 		"lastOnboardingVersion":  globalConfig.LastOnboardingVersion,
-		"hasApiKeys": map[string]bool{
-			"openai": globalConfig.APIKeys["openai"] != "",
-		},
+		"hasApiKeys":             hasAPIKeys,
 	}
 
 	c.JSON(http.StatusOK, safeConfig)
@@ -368,8 +494,8 @@ func (s *Server) handleGetConfig(c *gin.Context) {
 
 // ConfigUpdateRequest represents a config update request
 type ConfigUpdateRequest struct {
-	DefaultModel string            `json:"defaultModel"`
-	APIKeys      map[string]string `json:"apiKeys"`
+	DefaultModel string                           `json:"defaultModel"`
+	Providers    map[string]config.ProviderConfig `json:"providers"`
 }
 
 // handleUpdateConfig handles requests to update configuration
@@ -391,11 +517,26 @@ func (s *Server) handleUpdateConfig(c *gin.Context) {
 		globalConfig.DefaultModel = req.DefaultModel
 	}
 
-	// Update API keys
-	for provider, key := range req.APIKeys {
-		if key != "" {
-			globalConfig.APIKeys[provider] = key
+	// Update providers, field by field so a partial update (e.g. just a new
+	// API key) doesn't clobber the provider's other settings.
+	if globalConfig.Providers == nil {
+		globalConfig.Providers = make(map[string]config.ProviderConfig)
+	}
+	for name, update := range req.Providers {
+		existing := globalConfig.Providers[name]
+		if update.APIKey != "" {
+			existing.APIKey = update.APIKey
+		}
+		if update.BaseURL != "" {
+			existing.BaseURL = update.BaseURL
+		}
+		if update.DefaultModel != "" {
+			existing.DefaultModel = update.DefaultModel
 		}
+		if update.Headers != nil {
+			existing.Headers = update.Headers
+		}
+		globalConfig.Providers[name] = existing
 	}
 
 	// Save config
@@ -407,11 +548,35 @@ func (s *Server) handleUpdateConfig(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"success": true})
 }
 
+// aiProviders converts the saved provider configs into the shape
+// ai.NewModel expects, attaching presets to the openai provider if a
+// model registry is given.
+func aiProviders(globalConfig *config.GlobalConfig, presets *config.ModelRegistry) map[string]ai.ProviderConfig {
+	providers := make(map[string]ai.ProviderConfig, len(globalConfig.Providers))
+	for name, p := range globalConfig.Providers {
+		providers[name] = ai.ProviderConfig{
+			APIKey:          p.APIKey,
+			BaseURL:         p.BaseURL,
+			DefaultModel:    p.DefaultModel,
+			Headers:         p.Headers,
+			SecretAccessKey: p.SecretAccessKey,
+			Region:          p.Region,
+		}
+	}
+	if presets != nil {
+		openaiCfg := providers["openai"]
+		openaiCfg.Presets = presets
+		providers["openai"] = openaiCfg
+	}
+	return providers
+}
+
 func main() {
 	server, err := NewServer()
 	if err != nil {
 		log.Fatalf("Failed to create server: %v", err)
 	}
+	defer server.Close()
 
 	// Run server
 	if err := server.router.Run(":8080"); err != nil {
@@ -58,6 +58,26 @@ func TestServerRoutes(t *testing.T) {
 		assert.True(t, exists)
 	})
 
+	// Test the browse endpoint
+	t.Run("GET /api/browse", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/api/browse?path=.&sort_by=name&limit=5", nil)
+		server.router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response map[string]interface{}
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+
+		_, exists := response["entries"]
+		assert.True(t, exists)
+		_, exists = response["total"]
+		assert.True(t, exists)
+		_, exists = response["can_go_up"]
+		assert.True(t, exists)
+	})
+
 	// Test the chat endpoint
 	t.Run("POST /api/chat", func(t *testing.T) {
 		// Skip this test as it requires an API key
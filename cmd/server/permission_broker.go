@@ -0,0 +1,112 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"sync"
+
+	"github.com/cinience/aigo-kode/internal/core"
+	"github.com/gin-gonic/gin"
+)
+
+// HTTPPermissionBroker parks each tool call requiring permission behind a
+// pending-approval token until a client resolves it via
+// POST /api/permissions/:token, since there's no persistent connection (like
+// the CLI's terminal) to prompt synchronously over.
+type HTTPPermissionBroker struct {
+	mu      sync.Mutex
+	pending map[string]chan core.PermissionDecision
+}
+
+// NewHTTPPermissionBroker creates an empty HTTPPermissionBroker.
+func NewHTTPPermissionBroker() *HTTPPermissionBroker {
+	return &HTTPPermissionBroker{pending: make(map[string]chan core.PermissionDecision)}
+}
+
+// Request registers req under a new token and blocks until
+// ResolvePending is called with that token or ctx is done.
+func (b *HTTPPermissionBroker) Request(ctx context.Context, req core.PermissionRequest) (core.PermissionDecision, error) {
+	token, ch := b.register()
+	defer b.unregister(token)
+
+	select {
+	case <-ctx.Done():
+		return core.PermissionDenied, ctx.Err()
+	case decision := <-ch:
+		return decision, nil
+	}
+}
+
+func (b *HTTPPermissionBroker) register() (string, chan core.PermissionDecision) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	token := newToken()
+	ch := make(chan core.PermissionDecision, 1)
+	b.pending[token] = ch
+	return token, ch
+}
+
+func (b *HTTPPermissionBroker) unregister(token string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	delete(b.pending, token)
+}
+
+// ResolvePending delivers decision to the pending request identified by
+// token. It returns false if no such request is waiting (already resolved,
+// timed out, or never existed).
+func (b *HTTPPermissionBroker) ResolvePending(token string, decision core.PermissionDecision) bool {
+	b.mu.Lock()
+	ch, ok := b.pending[token]
+	b.mu.Unlock()
+	if !ok {
+		return false
+	}
+	ch <- decision
+	return true
+}
+
+func newToken() string {
+	buf := make([]byte, 16)
+	_, _ = rand.Read(buf)
+	return hex.EncodeToString(buf)
+}
+
+// resolvePermissionRequest is the JSON body POST /api/permissions/:token
+// expects.
+type resolvePermissionRequest struct {
+	Decision string `json:"decision"` // "denied", "once", or "always"
+}
+
+// handleResolvePermission resolves a pending tool-call approval parked by
+// HTTPPermissionBroker.Request.
+func (s *Server) handleResolvePermission(c *gin.Context) {
+	var req resolvePermissionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var decision core.PermissionDecision
+	switch req.Decision {
+	case "once":
+		decision = core.PermissionAllowedOnce
+	case "always":
+		decision = core.PermissionAllowedAlways
+	case "denied":
+		decision = core.PermissionDenied
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "decision must be one of: denied, once, always"})
+		return
+	}
+
+	if !s.permissionBroker.ResolvePending(c.Param("token"), decision) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "no pending permission request for that token"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"resolved": true})
+}
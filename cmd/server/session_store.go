@@ -0,0 +1,345 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cinience/aigo-kode/internal/core"
+)
+
+// defaultSessionTTL is how long a session can sit idle in memory before the
+// eviction loop persists it to the backend and drops it from the in-memory
+// map. A later GetSession call transparently reloads it from disk.
+const defaultSessionTTL = 30 * time.Minute
+
+// evictionInterval is how often StartEvictionLoop calls EvictIdle.
+const evictionInterval = 5 * time.Minute
+
+// PersistedSession is the on-disk representation of a session: everything
+// needed to rebuild a *core.Session without the live model/tool references,
+// which are re-attached from the current server configuration on load.
+type PersistedSession struct {
+	ID        string              `json:"id"`
+	Messages  []core.Message      `json:"messages"`
+	Config    *core.SessionConfig `json:"config"`
+	CreatedAt time.Time           `json:"createdAt"`
+	UpdatedAt time.Time           `json:"updatedAt"`
+}
+
+// SessionBackend persists sessions so they survive process restarts. The
+// default implementation stores one JSON file per session under
+// ~/.go-anon-kode/sessions; a BoltDB or SQLite backend can be added later
+// behind the same interface.
+type SessionBackend interface {
+	Save(session *PersistedSession) error
+	Load(id string) (*PersistedSession, error)
+	Delete(id string) error
+	List() ([]string, error)
+}
+
+// JSONFileBackend implements SessionBackend by writing one JSON file per
+// session into a directory.
+type JSONFileBackend struct {
+	dir string
+}
+
+// NewJSONFileBackend creates a JSONFileBackend rooted at dir, creating the
+// directory if it doesn't already exist.
+func NewJSONFileBackend(dir string) (*JSONFileBackend, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	return &JSONFileBackend{dir: dir}, nil
+}
+
+// errInvalidSessionID is returned when a caller-supplied session id isn't
+// safe to join into a filesystem path (see validateSessionID).
+var errInvalidSessionID = errors.New("invalid session id")
+
+// validateSessionID rejects session ids that could escape the backend's
+// directory when joined into a path: empty ids, path separators, and ".."
+// traversal segments. Session ids reach here straight from HTTP request
+// bodies and path/query parameters, so this is the last line of defense
+// against reading, overwriting, or deleting arbitrary files on disk.
+func validateSessionID(id string) error {
+	if id == "" || strings.ContainsAny(id, `/\`) || strings.Contains(id, "..") {
+		return errInvalidSessionID
+	}
+	return nil
+}
+
+func (b *JSONFileBackend) path(id string) (string, error) {
+	if err := validateSessionID(id); err != nil {
+		return "", err
+	}
+	return filepath.Join(b.dir, id+".json"), nil
+}
+
+// Save writes session to its JSON file, overwriting any previous contents.
+func (b *JSONFileBackend) Save(session *PersistedSession) error {
+	path, err := b.path(session.ID)
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(session, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// Load reads the session with the given id back from disk.
+func (b *JSONFileBackend) Load(id string) (*PersistedSession, error) {
+	path, err := b.path(id)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var session PersistedSession
+	if err := json.Unmarshal(data, &session); err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+// Delete removes the session's JSON file, if any.
+func (b *JSONFileBackend) Delete(id string) error {
+	path, err := b.path(id)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// List returns the ids of every session persisted in the backend.
+func (b *JSONFileBackend) List() ([]string, error) {
+	entries, err := os.ReadDir(b.dir)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".json" {
+			continue
+		}
+		ids = append(ids, entry.Name()[:len(entry.Name())-len(".json")])
+	}
+	return ids, nil
+}
+
+// sessionEntry pairs a live session with its bookkeeping fields.
+type sessionEntry struct {
+	session    *core.Session
+	createdAt  time.Time
+	lastAccess time.Time
+}
+
+// SessionStore manages active sessions, guarding the in-memory map with a
+// mutex and persisting sessions to a backend so conversation history
+// survives between HTTP calls and across restarts.
+type SessionStore struct {
+	mu       sync.RWMutex
+	sessions map[string]*sessionEntry
+	backend  SessionBackend
+	ttl      time.Duration
+	stop     chan struct{}
+}
+
+// NewSessionStore creates a SessionStore backed by backend. A nil backend
+// disables persistence (sessions only live as long as the process does),
+// which is useful in tests.
+func NewSessionStore(backend SessionBackend) *SessionStore {
+	return &SessionStore{
+		sessions: make(map[string]*sessionEntry),
+		backend:  backend,
+		ttl:      defaultSessionTTL,
+	}
+}
+
+// GetSession retrieves a session by ID, loading it from the backend or
+// creating a new one if it doesn't exist anywhere yet.
+func (s *SessionStore) GetSession(id string, model core.AIModel, tools []core.Tool) *core.Session {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if entry, ok := s.sessions[id]; ok {
+		entry.lastAccess = time.Now()
+		entry.session.Model = model
+		entry.session.Tools = tools
+		return entry.session
+	}
+
+	config := &core.SessionConfig{
+		ProjectPath:  ".",
+		SystemPrompt: "You are a helpful AI coding assistant. You can help with coding tasks, answer questions, and use tools to interact with the file system.",
+		MaxTokens:    4096,
+		Temperature:  0.7,
+	}
+
+	now := time.Now()
+	var session *core.Session
+	if s.backend != nil {
+		if persisted, err := s.backend.Load(id); err == nil {
+			if persisted.Config != nil {
+				config = persisted.Config
+			}
+			session = core.NewSession(model, tools, config)
+			session.Messages = persisted.Messages
+			now = persisted.CreatedAt
+		}
+	}
+	if session == nil {
+		session = core.NewSession(model, tools, config)
+	}
+	session.PermissionPolicy = &core.PathPolicy{Root: config.ProjectPath}
+
+	s.sessions[id] = &sessionEntry{session: session, createdAt: now, lastAccess: time.Now()}
+	return session
+}
+
+// Save persists the session's current state to the backend, if configured.
+func (s *SessionStore) Save(id string) error {
+	s.mu.RLock()
+	entry, ok := s.sessions[id]
+	s.mu.RUnlock()
+
+	if !ok {
+		return errors.New("session not found: " + id)
+	}
+	if s.backend == nil {
+		return nil
+	}
+
+	return s.backend.Save(&PersistedSession{
+		ID:        id,
+		Messages:  entry.session.Messages,
+		Config:    entry.session.Config,
+		CreatedAt: entry.createdAt,
+		UpdatedAt: time.Now(),
+	})
+}
+
+// Delete removes a session from memory and, if configured, the backend.
+func (s *SessionStore) Delete(id string) error {
+	s.mu.Lock()
+	delete(s.sessions, id)
+	s.mu.Unlock()
+
+	if s.backend == nil {
+		return nil
+	}
+	return s.backend.Delete(id)
+}
+
+// List returns the ids of every known session, in memory or persisted.
+func (s *SessionStore) List() ([]string, error) {
+	seen := make(map[string]bool)
+
+	s.mu.RLock()
+	for id := range s.sessions {
+		seen[id] = true
+	}
+	s.mu.RUnlock()
+
+	if s.backend != nil {
+		ids, err := s.backend.List()
+		if err != nil {
+			return nil, err
+		}
+		for _, id := range ids {
+			seen[id] = true
+		}
+	}
+
+	ids := make([]string, 0, len(seen))
+	for id := range seen {
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// Messages returns the message history for a session, loading it from the
+// backend if it isn't already in memory.
+func (s *SessionStore) Messages(id string) ([]core.Message, error) {
+	s.mu.RLock()
+	entry, ok := s.sessions[id]
+	s.mu.RUnlock()
+	if ok {
+		return entry.session.Messages, nil
+	}
+
+	if s.backend == nil {
+		return nil, errors.New("session not found: " + id)
+	}
+
+	persisted, err := s.backend.Load(id)
+	if err != nil {
+		return nil, err
+	}
+	return persisted.Messages, nil
+}
+
+// EvictIdle persists and drops any in-memory session that hasn't been
+// touched within the store's TTL, freeing memory while leaving the
+// conversation recoverable from the backend on next access.
+func (s *SessionStore) EvictIdle() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	for id, entry := range s.sessions {
+		if now.Sub(entry.lastAccess) < s.ttl {
+			continue
+		}
+		if s.backend != nil {
+			_ = s.backend.Save(&PersistedSession{
+				ID:        id,
+				Messages:  entry.session.Messages,
+				Config:    entry.session.Config,
+				CreatedAt: entry.createdAt,
+				UpdatedAt: now,
+			})
+		}
+		delete(s.sessions, id)
+	}
+}
+
+// StartEvictionLoop runs EvictIdle every evictionInterval until Stop is
+// called, so sessions idle past the store's TTL are actually reclaimed
+// instead of accumulating in memory for the life of the process.
+func (s *SessionStore) StartEvictionLoop() {
+	s.stop = make(chan struct{})
+	ticker := time.NewTicker(evictionInterval)
+
+	go func() {
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				s.EvictIdle()
+			case <-s.stop:
+				return
+			}
+		}
+	}()
+}
+
+// Stop ends the eviction loop started by StartEvictionLoop.
+func (s *SessionStore) Stop() {
+	if s.stop != nil {
+		close(s.stop)
+	}
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSessionStorePersistsAcrossInstances(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "sessionstoretest")
+	if err != nil {
+		t.Fatalf("Failed to create temp directory: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	backend, err := NewJSONFileBackend(tmpDir)
+	assert.NoError(t, err)
+
+	store := NewSessionStore(backend)
+	session := store.GetSession("s1", nil, nil)
+	session.AddUserMessage("hello")
+	assert.NoError(t, store.Save("s1"))
+
+	// A fresh store backed by the same directory should pick up the history.
+	reloaded := NewSessionStore(backend)
+	session = reloaded.GetSession("s1", nil, nil)
+	assert.Len(t, session.Messages, 2) // system + user message
+	assert.Equal(t, "hello", session.Messages[1].Content)
+
+	messages, err := reloaded.Messages("s1")
+	assert.NoError(t, err)
+	assert.Len(t, messages, 2)
+
+	ids, err := reloaded.List()
+	assert.NoError(t, err)
+	assert.Contains(t, ids, "s1")
+
+	assert.NoError(t, reloaded.Delete("s1"))
+	_, err = reloaded.Messages("s1")
+	assert.Error(t, err)
+}
+
+func TestJSONFileBackendRejectsPathTraversal(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "sessionstoretest")
+	if err != nil {
+		t.Fatalf("Failed to create temp directory: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	backend, err := NewJSONFileBackend(tmpDir)
+	assert.NoError(t, err)
+
+	for _, id := range []string{"../escape", "a/../../escape", "/etc/passwd", `..\escape`, ""} {
+		_, err := backend.Load(id)
+		assert.ErrorIs(t, err, errInvalidSessionID, "id %q", id)
+
+		err = backend.Save(&PersistedSession{ID: id})
+		assert.ErrorIs(t, err, errInvalidSessionID, "id %q", id)
+
+		err = backend.Delete(id)
+		assert.ErrorIs(t, err, errInvalidSessionID, "id %q", id)
+	}
+}
+
+func TestSessionStoreWithoutBackend(t *testing.T) {
+	store := NewSessionStore(nil)
+	session := store.GetSession("s1", nil, nil)
+	session.AddUserMessage("hi")
+
+	// Saving with no backend is a no-op, not an error.
+	assert.NoError(t, store.Save("s1"))
+
+	messages, err := store.Messages("s1")
+	assert.NoError(t, err)
+	assert.Len(t, messages, 2)
+}
@@ -0,0 +1,93 @@
+// Package agents loads task-specialized agent profiles: a system prompt,
+// the subset of tools the agent is allowed to call, which model it should
+// run on, and any files whose contents should always be pinned into its
+// context.
+package agents
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Agent is one agents/*.yaml profile.
+type Agent struct {
+	Name string `yaml:"name"`
+	// SystemPrompt replaces the session's default system prompt when this
+	// agent is active.
+	SystemPrompt string `yaml:"system_prompt,omitempty"`
+	// AllowedTools restricts the session to tools with these names. An
+	// empty list means no tools at all, not "all tools" - callers that
+	// want every tool simply don't select an agent.
+	AllowedTools []string `yaml:"allowed_tools,omitempty"`
+	// Model, if set, overrides the "provider:model" identifier the caller
+	// would otherwise use.
+	Model string `yaml:"model,omitempty"`
+	// ContextFiles are paths whose contents should be pinned into the
+	// conversation whenever this agent is used.
+	ContextFiles []string `yaml:"context_files,omitempty"`
+}
+
+// Registry holds the agent profiles loaded from a directory of
+// ~/.go-anon-kode/agents/*.yaml files.
+type Registry struct {
+	agents map[string]*Agent
+}
+
+// NewRegistry loads every *.yaml/*.yml file in dir as an Agent profile. A
+// missing directory is not an error - it just means no agents are
+// configured yet.
+func NewRegistry(dir string) (*Registry, error) {
+	r := &Registry{agents: make(map[string]*Agent)}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return r, nil
+		}
+		return nil, err
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		ext := strings.ToLower(filepath.Ext(entry.Name()))
+		if ext != ".yaml" && ext != ".yml" {
+			continue
+		}
+
+		agent, err := loadAgent(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("agents: loading %s: %w", entry.Name(), err)
+		}
+		if agent.Name == "" {
+			agent.Name = strings.TrimSuffix(entry.Name(), ext)
+		}
+		r.agents[agent.Name] = agent
+	}
+
+	return r, nil
+}
+
+func loadAgent(path string) (*Agent, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var agent Agent
+	if err := yaml.Unmarshal(data, &agent); err != nil {
+		return nil, err
+	}
+	return &agent, nil
+}
+
+// Get returns the agent profile registered under name, if any.
+func (r *Registry) Get(name string) (*Agent, bool) {
+	agent, ok := r.agents[name]
+	return agent, ok
+}
@@ -0,0 +1,58 @@
+package agents
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegistryLoadsAgents(t *testing.T) {
+	dir, err := os.MkdirTemp("", "agentstest")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	err = os.WriteFile(filepath.Join(dir, "coder.yaml"), []byte(`
+name: coder
+system_prompt: "You are a careful, senior Go engineer."
+allowed_tools: ["FileRead", "FileWrite", "Bash"]
+model: "anthropic:claude-3-5-sonnet-latest"
+`), 0644)
+	assert.NoError(t, err)
+
+	registry, err := NewRegistry(dir)
+	assert.NoError(t, err)
+
+	agent, ok := registry.Get("coder")
+	assert.True(t, ok)
+	assert.Equal(t, "anthropic:claude-3-5-sonnet-latest", agent.Model)
+	assert.Equal(t, []string{"FileRead", "FileWrite", "Bash"}, agent.AllowedTools)
+
+	_, ok = registry.Get("missing")
+	assert.False(t, ok)
+}
+
+func TestRegistryDefaultsNameToFilename(t *testing.T) {
+	dir, err := os.MkdirTemp("", "agentstest")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	err = os.WriteFile(filepath.Join(dir, "research.yaml"), []byte(`allowed_tools: ["Grep", "Glob"]`), 0644)
+	assert.NoError(t, err)
+
+	registry, err := NewRegistry(dir)
+	assert.NoError(t, err)
+
+	agent, ok := registry.Get("research")
+	assert.True(t, ok)
+	assert.Equal(t, []string{"Grep", "Glob"}, agent.AllowedTools)
+}
+
+func TestRegistryMissingDirIsNotAnError(t *testing.T) {
+	registry, err := NewRegistry(filepath.Join(os.TempDir(), "does-not-exist-kode-agents"))
+	assert.NoError(t, err)
+
+	_, ok := registry.Get("anything")
+	assert.False(t, ok)
+}
@@ -81,6 +81,10 @@ func (t *MockTool) Description() string {
 	return t.description
 }
 
+func (t *MockTool) Arguments() string {
+	return ""
+}
+
 func (t *MockTool) Execute(ctx context.Context, input map[string]interface{}) (interface{}, error) {
 	return map[string]string{"result": "mock result"}, nil
 }
@@ -89,6 +93,10 @@ func (t *MockTool) ValidateInput(input map[string]interface{}) error {
 	return nil
 }
 
+func (t *MockTool) OutputSchema() string {
+	return ""
+}
+
 func (t *MockTool) IsReadOnly() bool {
 	return true
 }
@@ -100,7 +108,7 @@ func (t *MockTool) RequiresPermission(input map[string]interface{}) bool {
 // MockModel implements the AIModel interface for testing
 type MockModel struct{}
 
-func (m *MockModel) Query(ctx context.Context, messages []core.Message, tools []core.Tool) (*core.Response, error) {
+func (m *MockModel) Query(ctx context.Context, messages []core.Message, tools []core.Tool, opts *core.QueryOptions) (*core.Response, error) {
 	return &core.Response{
 		Content:   "Mock response",
 		ToolCalls: []core.ToolCall{},
@@ -113,7 +121,7 @@ func (m *MockModel) Query(ctx context.Context, messages []core.Message, tools []
 	}, nil
 }
 
-func (m *MockModel) StreamQuery(ctx context.Context, messages []core.Message, tools []core.Tool) (<-chan core.ResponseChunk, error) {
+func (m *MockModel) StreamQuery(ctx context.Context, messages []core.Message, tools []core.Tool, opts *core.QueryOptions) (<-chan core.ResponseChunk, error) {
 	ch := make(chan core.ResponseChunk)
 
 	go func() {
@@ -0,0 +1,323 @@
+package ai
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/cinience/aigo-kode/internal/core"
+)
+
+const (
+	anthropicDefaultBaseURL   = "https://api.anthropic.com"
+	anthropicAPIVersion       = "2023-06-01"
+	anthropicDefaultModel     = "claude-3-5-sonnet-latest"
+	anthropicDefaultMaxTokens = 4096
+)
+
+func init() {
+	RegisterProvider("anthropic", func(cfg ProviderConfig) (core.AIModel, error) {
+		return NewAnthropicModel(cfg.APIKey, cfg.DefaultModel, cfg.BaseURL)
+	})
+}
+
+// AnthropicModel implements the AIModel interface for Anthropic's Claude
+// messages API, using native tool_use/tool_result content blocks instead of
+// OpenAI-style function-call messages.
+type AnthropicModel struct {
+	httpClient *http.Client
+	apiKey     string
+	baseURL    string
+	modelName  string
+	maxTokens  int
+}
+
+// NewAnthropicModel creates a new Anthropic model
+func NewAnthropicModel(apiKey, modelName, baseURL string) (*AnthropicModel, error) {
+	if apiKey == "" {
+		return nil, errors.New("API key is required")
+	}
+
+	if modelName == "" {
+		modelName = anthropicDefaultModel
+	}
+
+	if baseURL == "" {
+		baseURL = anthropicDefaultBaseURL
+	}
+
+	return &AnthropicModel{
+		httpClient: http.DefaultClient,
+		apiKey:     apiKey,
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		modelName:  modelName,
+		maxTokens:  anthropicDefaultMaxTokens,
+	}, nil
+}
+
+// Name returns the model name
+func (m *AnthropicModel) Name() string {
+	return m.modelName
+}
+
+// Provider returns the model provider
+func (m *AnthropicModel) Provider() string {
+	return "Anthropic"
+}
+
+// anthropicContentBlock is a single block of a messages-API content array.
+// Not every field applies to every block type: text blocks use Text, tool_use
+// blocks use ID/Name/Input, and tool_result blocks use ToolUseID/Content.
+type anthropicContentBlock struct {
+	Type      string          `json:"type"`
+	Text      string          `json:"text,omitempty"`
+	ID        string          `json:"id,omitempty"`
+	Name      string          `json:"name,omitempty"`
+	Input     json.RawMessage `json:"input,omitempty"`
+	ToolUseID string          `json:"tool_use_id,omitempty"`
+	Content   string          `json:"content,omitempty"`
+}
+
+type anthropicMessage struct {
+	Role    string                  `json:"role"`
+	Content []anthropicContentBlock `json:"content"`
+}
+
+type anthropicTool struct {
+	Name        string          `json:"name"`
+	Description string          `json:"description,omitempty"`
+	InputSchema json.RawMessage `json:"input_schema"`
+}
+
+type anthropicRequest struct {
+	Model     string             `json:"model"`
+	System    string             `json:"system,omitempty"`
+	Messages  []anthropicMessage `json:"messages"`
+	MaxTokens int                `json:"max_tokens"`
+	Tools     []anthropicTool    `json:"tools,omitempty"`
+	Stream    bool               `json:"stream,omitempty"`
+}
+
+type anthropicUsage struct {
+	InputTokens  int `json:"input_tokens"`
+	OutputTokens int `json:"output_tokens"`
+}
+
+type anthropicResponse struct {
+	Content    []anthropicContentBlock `json:"content"`
+	StopReason string                  `json:"stop_reason"`
+	Usage      anthropicUsage          `json:"usage"`
+	Error      *anthropicAPIError      `json:"error,omitempty"`
+}
+
+type anthropicAPIError struct {
+	Type    string `json:"type"`
+	Message string `json:"message"`
+}
+
+type anthropicStreamEvent struct {
+	Type  string `json:"type"`
+	Delta struct {
+		Type string `json:"type"`
+		Text string `json:"text"`
+	} `json:"delta"`
+	Error *anthropicAPIError `json:"error"`
+}
+
+// Query sends a query to the model and returns a response. The messages
+// API has no response_format/grammar equivalent, so opts is accepted for
+// interface compatibility but otherwise unused.
+func (m *AnthropicModel) Query(ctx context.Context, messages []core.Message, tools []core.Tool, opts *core.QueryOptions) (*core.Response, error) {
+	reqBody := m.buildRequest(messages, tools, false)
+
+	var resp anthropicResponse
+	if err := m.doRequest(ctx, reqBody, &resp); err != nil {
+		return nil, err
+	}
+	if resp.Error != nil {
+		return nil, fmt.Errorf("Anthropic API error: %s", resp.Error.Message)
+	}
+
+	var content string
+	var toolCalls []core.ToolCall
+	for _, block := range resp.Content {
+		switch block.Type {
+		case "text":
+			content += block.Text
+		case "tool_use":
+			input := make(map[string]interface{})
+			_ = json.Unmarshal(block.Input, &input)
+			toolCalls = append(toolCalls, core.ToolCall{
+				ID:       block.ID,
+				ToolName: block.Name,
+				Input:    input,
+			})
+		}
+	}
+
+	return &core.Response{
+		Content:   content,
+		ToolCalls: toolCalls,
+		Usage: core.Usage{
+			PromptTokens:     resp.Usage.InputTokens,
+			CompletionTokens: resp.Usage.OutputTokens,
+			TotalTokens:      resp.Usage.InputTokens + resp.Usage.OutputTokens,
+		},
+		FinishReason: resp.StopReason,
+	}, nil
+}
+
+// StreamQuery sends a query to the model and returns a stream of response
+// chunks. See Query for why opts is unused here.
+func (m *AnthropicModel) StreamQuery(ctx context.Context, messages []core.Message, tools []core.Tool, opts *core.QueryOptions) (<-chan core.ResponseChunk, error) {
+	reqBody := m.buildRequest(messages, tools, true)
+
+	body, err := json.Marshal(reqBody)
+	if err != nil {
+		return nil, err
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, m.baseURL+"/v1/messages", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	m.setHeaders(httpReq)
+
+	httpResp, err := m.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("Anthropic API error: %w", err)
+	}
+	if httpResp.StatusCode != http.StatusOK {
+		defer httpResp.Body.Close()
+		return nil, fmt.Errorf("Anthropic API error: unexpected status %d", httpResp.StatusCode)
+	}
+
+	responseCh := make(chan core.ResponseChunk)
+
+	go func() {
+		defer close(responseCh)
+		defer httpResp.Body.Close()
+
+		scanner := bufio.NewScanner(httpResp.Body)
+		for scanner.Scan() {
+			line := scanner.Text()
+			data, ok := strings.CutPrefix(line, "data: ")
+			if !ok {
+				continue
+			}
+
+			var event anthropicStreamEvent
+			if err := json.Unmarshal([]byte(data), &event); err != nil {
+				continue
+			}
+
+			switch event.Type {
+			case "content_block_delta":
+				if event.Delta.Type == "text_delta" && event.Delta.Text != "" {
+					responseCh <- core.ResponseChunk{Content: event.Delta.Text}
+				}
+			case "message_stop":
+				responseCh <- core.ResponseChunk{IsDone: true}
+				return
+			case "error":
+				msg := "unknown error"
+				if event.Error != nil {
+					msg = event.Error.Message
+				}
+				responseCh <- core.ResponseChunk{Error: fmt.Errorf("Anthropic API error: %s", msg), IsDone: true}
+				return
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			responseCh <- core.ResponseChunk{Error: err, IsDone: true}
+		}
+	}()
+
+	return responseCh, nil
+}
+
+// buildRequest converts the session's messages and tools into an Anthropic
+// messages-API request. System messages become the top-level System field,
+// since Anthropic doesn't accept a "system" role inside Messages; "tool"
+// role messages (the output of a prior tool call) become a user message
+// carrying a tool_result block.
+func (m *AnthropicModel) buildRequest(messages []core.Message, tools []core.Tool, stream bool) anthropicRequest {
+	req := anthropicRequest{
+		Model:     m.modelName,
+		MaxTokens: m.maxTokens,
+		Stream:    stream,
+	}
+
+	for _, msg := range messages {
+		switch msg.Role {
+		case "system":
+			req.System = fmt.Sprintf("%v", msg.Content)
+		case "tool":
+			req.Messages = append(req.Messages, anthropicMessage{
+				Role: "user",
+				Content: []anthropicContentBlock{{
+					Type:      "tool_result",
+					ToolUseID: msg.ToolCallID,
+					Content:   fmt.Sprintf("%v", msg.Content),
+				}},
+			})
+		default:
+			var blocks []anthropicContentBlock
+			if text := fmt.Sprintf("%v", msg.Content); text != "" {
+				blocks = append(blocks, anthropicContentBlock{Type: "text", Text: text})
+			}
+			for _, toolCall := range msg.ToolCalls {
+				input, _ := json.Marshal(toolCall.Input)
+				blocks = append(blocks, anthropicContentBlock{
+					Type:  "tool_use",
+					ID:    toolCall.ID,
+					Name:  toolCall.ToolName,
+					Input: input,
+				})
+			}
+			req.Messages = append(req.Messages, anthropicMessage{Role: msg.Role, Content: blocks})
+		}
+	}
+
+	for _, tool := range tools {
+		req.Tools = append(req.Tools, anthropicTool{
+			Name:        tool.Name(),
+			Description: tool.Description(),
+			InputSchema: json.RawMessage(fmt.Sprintf(`{"type":"object","properties":%s}`, tool.Arguments())),
+		})
+	}
+
+	return req
+}
+
+func (m *AnthropicModel) doRequest(ctx context.Context, reqBody anthropicRequest, out interface{}) error {
+	body, err := json.Marshal(reqBody)
+	if err != nil {
+		return err
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, m.baseURL+"/v1/messages", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	m.setHeaders(httpReq)
+
+	httpResp, err := m.httpClient.Do(httpReq)
+	if err != nil {
+		return fmt.Errorf("Anthropic API error: %w", err)
+	}
+	defer httpResp.Body.Close()
+
+	return json.NewDecoder(httpResp.Body).Decode(out)
+}
+
+func (m *AnthropicModel) setHeaders(req *http.Request) {
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("x-api-key", m.apiKey)
+	req.Header.Set("anthropic-version", anthropicAPIVersion)
+}
@@ -0,0 +1,359 @@
+package ai
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/cinience/aigo-kode/internal/core"
+)
+
+const (
+	bedrockDefaultRegion    = "us-east-1"
+	bedrockDefaultModel     = "anthropic.claude-3-5-sonnet-20241022-v2:0"
+	bedrockService          = "bedrock"
+	bedrockAnthropicVersion = "bedrock-2023-05-31"
+)
+
+func init() {
+	RegisterProvider("bedrock", func(cfg ProviderConfig) (core.AIModel, error) {
+		return NewBedrockModel(cfg.APIKey, cfg.SecretAccessKey, cfg.Region, cfg.DefaultModel, cfg.BaseURL)
+	})
+}
+
+// BedrockModel implements the AIModel interface for AWS Bedrock, signing
+// every request with SigV4 rather than a bearer token. It supports Claude
+// model IDs (the "anthropic." prefix), which share Anthropic's tool_use/
+// tool_result content-block schema, and Meta's Llama model IDs (the
+// "meta." prefix), which have no native tool-calling support on Bedrock.
+type BedrockModel struct {
+	httpClient      *http.Client
+	accessKeyID     string
+	secretAccessKey string
+	region          string
+	modelName       string
+	baseURL         string
+}
+
+// NewBedrockModel creates a new Bedrock model
+func NewBedrockModel(accessKeyID, secretAccessKey, region, modelName, baseURL string) (*BedrockModel, error) {
+	if accessKeyID == "" || secretAccessKey == "" {
+		return nil, errors.New("AWS credentials are required")
+	}
+
+	if region == "" {
+		region = bedrockDefaultRegion
+	}
+
+	if modelName == "" {
+		modelName = bedrockDefaultModel
+	}
+
+	if baseURL == "" {
+		baseURL = fmt.Sprintf("https://bedrock-runtime.%s.amazonaws.com", region)
+	}
+
+	return &BedrockModel{
+		httpClient:      http.DefaultClient,
+		accessKeyID:     accessKeyID,
+		secretAccessKey: secretAccessKey,
+		region:          region,
+		modelName:       modelName,
+		baseURL:         strings.TrimRight(baseURL, "/"),
+	}, nil
+}
+
+// Name returns the model name
+func (m *BedrockModel) Name() string {
+	return m.modelName
+}
+
+// Provider returns the model provider
+func (m *BedrockModel) Provider() string {
+	return "Bedrock"
+}
+
+func (m *BedrockModel) isClaudeModel() bool {
+	return strings.HasPrefix(m.modelName, "anthropic.")
+}
+
+// bedrockClaudeRequest is the InvokeModel request body Bedrock expects for
+// Claude model IDs - identical to Anthropic's own messages API, minus the
+// top-level "model" field (the model is named in the URL instead).
+type bedrockClaudeRequest struct {
+	AnthropicVersion string             `json:"anthropic_version"`
+	System           string             `json:"system,omitempty"`
+	Messages         []anthropicMessage `json:"messages"`
+	MaxTokens        int                `json:"max_tokens"`
+	Tools            []anthropicTool    `json:"tools,omitempty"`
+}
+
+// bedrockLlamaRequest is the InvokeModel request body Bedrock expects for
+// Llama model IDs. Llama has no notion of structured messages or tool
+// calls, so the conversation is flattened into a single prompt string.
+type bedrockLlamaRequest struct {
+	Prompt      string  `json:"prompt"`
+	MaxGenLen   int     `json:"max_gen_len"`
+	Temperature float64 `json:"temperature,omitempty"`
+}
+
+type bedrockLlamaResponse struct {
+	Generation           string `json:"generation"`
+	PromptTokenCount     int    `json:"prompt_token_count"`
+	GenerationTokenCount int    `json:"generation_token_count"`
+	StopReason           string `json:"stop_reason"`
+}
+
+// Query sends a query to the model and returns a response. opts is
+// accepted for interface compatibility but unused - Bedrock's InvokeModel
+// API has no response_format/grammar equivalent for either model family.
+func (m *BedrockModel) Query(ctx context.Context, messages []core.Message, tools []core.Tool, opts *core.QueryOptions) (*core.Response, error) {
+	if m.isClaudeModel() {
+		return m.queryClaude(ctx, messages, tools)
+	}
+	return m.queryLlama(ctx, messages)
+}
+
+func (m *BedrockModel) queryClaude(ctx context.Context, messages []core.Message, tools []core.Tool) (*core.Response, error) {
+	reqBody := m.buildClaudeRequest(messages, tools)
+
+	var resp anthropicResponse
+	if err := m.invoke(ctx, reqBody, &resp); err != nil {
+		return nil, err
+	}
+
+	var content string
+	var toolCalls []core.ToolCall
+	for _, block := range resp.Content {
+		switch block.Type {
+		case "text":
+			content += block.Text
+		case "tool_use":
+			input := make(map[string]interface{})
+			_ = json.Unmarshal(block.Input, &input)
+			toolCalls = append(toolCalls, core.ToolCall{
+				ID:       block.ID,
+				ToolName: block.Name,
+				Input:    input,
+			})
+		}
+	}
+
+	return &core.Response{
+		Content:   content,
+		ToolCalls: toolCalls,
+		Usage: core.Usage{
+			PromptTokens:     resp.Usage.InputTokens,
+			CompletionTokens: resp.Usage.OutputTokens,
+			TotalTokens:      resp.Usage.InputTokens + resp.Usage.OutputTokens,
+		},
+		FinishReason: resp.StopReason,
+	}, nil
+}
+
+func (m *BedrockModel) queryLlama(ctx context.Context, messages []core.Message) (*core.Response, error) {
+	reqBody := bedrockLlamaRequest{
+		Prompt:    bedrockPrompt(messages),
+		MaxGenLen: 2048,
+	}
+
+	var resp bedrockLlamaResponse
+	if err := m.invoke(ctx, reqBody, &resp); err != nil {
+		return nil, err
+	}
+
+	return &core.Response{
+		Content: resp.Generation,
+		Usage: core.Usage{
+			PromptTokens:     resp.PromptTokenCount,
+			CompletionTokens: resp.GenerationTokenCount,
+			TotalTokens:      resp.PromptTokenCount + resp.GenerationTokenCount,
+		},
+		FinishReason: resp.StopReason,
+	}, nil
+}
+
+// StreamQuery sends a query to the model and returns a stream of response
+// chunks. Bedrock's streaming variant (InvokeModelWithResponseStream) frames
+// its body as a binary AWS event stream rather than SSE or line-delimited
+// JSON, which this package doesn't otherwise need to parse. Rather than add
+// an event-stream decoder for Bedrock alone, StreamQuery buffers the full
+// InvokeModel response and replays it as a single content chunk followed by
+// the terminal chunk - callers see the same two-chunk shape a genuinely
+// streamed one-token response would produce.
+func (m *BedrockModel) StreamQuery(ctx context.Context, messages []core.Message, tools []core.Tool, opts *core.QueryOptions) (<-chan core.ResponseChunk, error) {
+	resp, err := m.Query(ctx, messages, tools, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	responseCh := make(chan core.ResponseChunk, 2)
+	responseCh <- core.ResponseChunk{Content: resp.Content}
+	responseCh <- core.ResponseChunk{ToolCalls: resp.ToolCalls, FinishReason: resp.FinishReason, Usage: resp.Usage, IsDone: true}
+	close(responseCh)
+	return responseCh, nil
+}
+
+// buildClaudeRequest converts the session's messages and tools into a
+// Bedrock Claude request, reusing AnthropicModel's content-block types
+// since Bedrock's Claude schema is the same messages API.
+func (m *BedrockModel) buildClaudeRequest(messages []core.Message, tools []core.Tool) bedrockClaudeRequest {
+	req := bedrockClaudeRequest{
+		AnthropicVersion: bedrockAnthropicVersion,
+		MaxTokens:        anthropicDefaultMaxTokens,
+	}
+
+	for _, msg := range messages {
+		switch msg.Role {
+		case "system":
+			req.System = fmt.Sprintf("%v", msg.Content)
+		case "tool":
+			req.Messages = append(req.Messages, anthropicMessage{
+				Role: "user",
+				Content: []anthropicContentBlock{{
+					Type:      "tool_result",
+					ToolUseID: msg.ToolCallID,
+					Content:   fmt.Sprintf("%v", msg.Content),
+				}},
+			})
+		default:
+			var blocks []anthropicContentBlock
+			if text := fmt.Sprintf("%v", msg.Content); text != "" {
+				blocks = append(blocks, anthropicContentBlock{Type: "text", Text: text})
+			}
+			for _, toolCall := range msg.ToolCalls {
+				input, _ := json.Marshal(toolCall.Input)
+				blocks = append(blocks, anthropicContentBlock{
+					Type:  "tool_use",
+					ID:    toolCall.ID,
+					Name:  toolCall.ToolName,
+					Input: input,
+				})
+			}
+			req.Messages = append(req.Messages, anthropicMessage{Role: msg.Role, Content: blocks})
+		}
+	}
+
+	for _, tool := range tools {
+		req.Tools = append(req.Tools, anthropicTool{
+			Name:        tool.Name(),
+			Description: tool.Description(),
+			InputSchema: json.RawMessage(fmt.Sprintf(`{"type":"object","properties":%s}`, tool.Arguments())),
+		})
+	}
+
+	return req
+}
+
+// bedrockPrompt flattens messages into a single prompt string for model
+// families with no structured chat format on Bedrock.
+func bedrockPrompt(messages []core.Message) string {
+	var b strings.Builder
+	for _, msg := range messages {
+		fmt.Fprintf(&b, "%s: %v\n", msg.Role, msg.Content)
+	}
+	return b.String()
+}
+
+func (m *BedrockModel) invoke(ctx context.Context, reqBody interface{}, out interface{}) error {
+	body, err := json.Marshal(reqBody)
+	if err != nil {
+		return err
+	}
+
+	path := fmt.Sprintf("/model/%s/invoke", url.PathEscape(m.modelName))
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, m.baseURL+path, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("Accept", "application/json")
+
+	if err := m.signRequest(httpReq, body); err != nil {
+		return err
+	}
+
+	httpResp, err := m.httpClient.Do(httpReq)
+	if err != nil {
+		return fmt.Errorf("Bedrock API error: %w", err)
+	}
+	defer httpResp.Body.Close()
+
+	if httpResp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(httpResp.Body)
+		return fmt.Errorf("Bedrock API error: unexpected status %d: %s", httpResp.StatusCode, respBody)
+	}
+
+	return json.NewDecoder(httpResp.Body).Decode(out)
+}
+
+// signRequest signs req with AWS Signature Version 4, the scheme Bedrock
+// requires instead of a bearer token. It hashes and signs body directly
+// rather than re-reading req.Body, since the caller already has it in hand.
+func (m *BedrockModel) signRequest(req *http.Request, body []byte) error {
+	now := time.Now().UTC()
+	amzDate := now.Format("20060102T150405Z")
+	dateStamp := now.Format("20060102")
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Host = req.URL.Host
+
+	payloadHash := hashHex(body)
+	canonicalHeaders := fmt.Sprintf("content-type:%s\nhost:%s\nx-amz-date:%s\n", req.Header.Get("Content-Type"), req.Host, amzDate)
+	signedHeaders := "content-type;host;x-amz-date"
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.RawQuery,
+		canonicalHeaders,
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	credentialScope := fmt.Sprintf("%s/%s/%s/aws4_request", dateStamp, m.region, bedrockService)
+	stringToSign := strings.Join([]string{
+		"AWS4-HMAC-SHA256",
+		amzDate,
+		credentialScope,
+		hashHex([]byte(canonicalRequest)),
+	}, "\n")
+
+	signingKey := bedrockSigningKey(m.secretAccessKey, dateStamp, m.region, bedrockService)
+	signature := hex.EncodeToString(hmacSHA256(signingKey, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf(
+		"AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		m.accessKeyID, credentialScope, signedHeaders, signature,
+	))
+
+	return nil
+}
+
+func bedrockSigningKey(secretAccessKey, dateStamp, region, service string) []byte {
+	kDate := hmacSHA256([]byte("AWS4"+secretAccessKey), dateStamp)
+	kRegion := hmacSHA256(kDate, region)
+	kService := hmacSHA256(kRegion, service)
+	return hmacSHA256(kService, "aws4_request")
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
+
+func hashHex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
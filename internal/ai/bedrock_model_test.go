@@ -0,0 +1,41 @@
+package ai
+
+import (
+	"testing"
+
+	"github.com/cinience/aigo-kode/internal/core"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestBedrockBuildClaudeRequestPairsToolUseAndResult mirrors
+// TestAnthropicBuildRequestPairsToolUseAndResult: Bedrock's Claude request
+// body reuses the same anthropicContentBlock type, so it must pair
+// tool_result.tool_use_id with the originating tool_use.id the same way.
+func TestBedrockBuildClaudeRequestPairsToolUseAndResult(t *testing.T) {
+	m := &BedrockModel{modelName: bedrockDefaultModel}
+
+	messages := []core.Message{
+		{Role: "user", Content: "what's in the repo root?"},
+		{Role: "assistant", Content: "", ToolCalls: []core.ToolCall{
+			{ID: "toolu_1", ToolName: "LS", Input: map[string]interface{}{"path": "."}},
+		}},
+		{Role: "tool", Content: "README.md\nmain.go", ToolCallID: "toolu_1", ToolName: "LS"},
+		{Role: "assistant", Content: "The repo root has README.md and main.go."},
+	}
+
+	req := m.buildClaudeRequest(messages, nil)
+
+	assert.Len(t, req.Messages, 4)
+
+	toolUse := req.Messages[1].Content
+	assert.Len(t, toolUse, 1)
+	assert.Equal(t, "tool_use", toolUse[0].Type)
+	assert.Equal(t, "toolu_1", toolUse[0].ID)
+	assert.Equal(t, "LS", toolUse[0].Name)
+
+	toolResult := req.Messages[2].Content
+	assert.Len(t, toolResult, 1)
+	assert.Equal(t, "tool_result", toolResult[0].Type)
+	assert.Equal(t, "toolu_1", toolResult[0].ToolUseID)
+	assert.Equal(t, "README.md\nmain.go", toolResult[0].Content)
+}
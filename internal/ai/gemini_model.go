@@ -0,0 +1,327 @@
+package ai
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/cinience/aigo-kode/internal/core"
+)
+
+const (
+	geminiDefaultBaseURL = "https://generativelanguage.googleapis.com"
+	geminiDefaultModel   = "gemini-1.5-pro"
+)
+
+func init() {
+	RegisterProvider("gemini", func(cfg ProviderConfig) (core.AIModel, error) {
+		return NewGeminiModel(cfg.APIKey, cfg.DefaultModel, cfg.BaseURL)
+	})
+}
+
+// GeminiModel implements the AIModel interface for Google's Gemini
+// generateContent API.
+type GeminiModel struct {
+	httpClient *http.Client
+	apiKey     string
+	baseURL    string
+	modelName  string
+}
+
+// NewGeminiModel creates a new Gemini model
+func NewGeminiModel(apiKey, modelName, baseURL string) (*GeminiModel, error) {
+	if apiKey == "" {
+		return nil, errors.New("API key is required")
+	}
+
+	if modelName == "" {
+		modelName = geminiDefaultModel
+	}
+
+	if baseURL == "" {
+		baseURL = geminiDefaultBaseURL
+	}
+
+	return &GeminiModel{
+		httpClient: http.DefaultClient,
+		apiKey:     apiKey,
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		modelName:  modelName,
+	}, nil
+}
+
+// Name returns the model name
+func (m *GeminiModel) Name() string {
+	return m.modelName
+}
+
+// Provider returns the model provider
+func (m *GeminiModel) Provider() string {
+	return "Gemini"
+}
+
+type geminiPart struct {
+	Text         string              `json:"text,omitempty"`
+	FunctionCall *geminiFunctionCall `json:"functionCall,omitempty"`
+	FunctionResp *geminiFunctionResp `json:"functionResponse,omitempty"`
+}
+
+type geminiFunctionCall struct {
+	Name string          `json:"name"`
+	Args json.RawMessage `json:"args"`
+}
+
+type geminiFunctionResp struct {
+	Name     string          `json:"name"`
+	Response json.RawMessage `json:"response"`
+}
+
+type geminiContent struct {
+	Role  string       `json:"role,omitempty"`
+	Parts []geminiPart `json:"parts"`
+}
+
+type geminiFunctionDeclaration struct {
+	Name        string          `json:"name"`
+	Description string          `json:"description,omitempty"`
+	Parameters  json.RawMessage `json:"parameters"`
+}
+
+type geminiTool struct {
+	FunctionDeclarations []geminiFunctionDeclaration `json:"functionDeclarations"`
+}
+
+type geminiGenerationConfig struct {
+	ResponseMimeType string          `json:"responseMimeType,omitempty"`
+	ResponseSchema   json.RawMessage `json:"responseSchema,omitempty"`
+}
+
+type geminiRequest struct {
+	SystemInstruction *geminiContent          `json:"systemInstruction,omitempty"`
+	Contents          []geminiContent         `json:"contents"`
+	Tools             []geminiTool            `json:"tools,omitempty"`
+	GenerationConfig  *geminiGenerationConfig `json:"generationConfig,omitempty"`
+}
+
+type geminiUsageMetadata struct {
+	PromptTokenCount     int `json:"promptTokenCount"`
+	CandidatesTokenCount int `json:"candidatesTokenCount"`
+	TotalTokenCount      int `json:"totalTokenCount"`
+}
+
+type geminiCandidate struct {
+	Content      geminiContent `json:"content"`
+	FinishReason string        `json:"finishReason"`
+}
+
+type geminiResponse struct {
+	Candidates    []geminiCandidate   `json:"candidates"`
+	UsageMetadata geminiUsageMetadata `json:"usageMetadata"`
+	Error         *geminiAPIError     `json:"error"`
+}
+
+type geminiAPIError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// geminiGenerationConfigFor builds the generationConfig that constrains
+// Gemini's response to opts.ResponseSchema. opts.Grammar has no Gemini
+// equivalent and is ignored.
+func geminiGenerationConfigFor(opts *core.QueryOptions) *geminiGenerationConfig {
+	if opts == nil || opts.ResponseSchema == "" {
+		return nil
+	}
+	return &geminiGenerationConfig{
+		ResponseMimeType: "application/json",
+		ResponseSchema:   json.RawMessage(opts.ResponseSchema),
+	}
+}
+
+// Query sends a query to the model and returns a response
+func (m *GeminiModel) Query(ctx context.Context, messages []core.Message, tools []core.Tool, opts *core.QueryOptions) (*core.Response, error) {
+	reqBody := m.buildRequest(messages, tools)
+	reqBody.GenerationConfig = geminiGenerationConfigFor(opts)
+
+	endpoint := fmt.Sprintf("%s/v1beta/models/%s:generateContent?key=%s", m.baseURL, m.modelName, url.QueryEscape(m.apiKey))
+
+	var resp geminiResponse
+	if err := m.doRequest(ctx, endpoint, reqBody, &resp); err != nil {
+		return nil, err
+	}
+	if resp.Error != nil {
+		return nil, fmt.Errorf("Gemini API error: %s", resp.Error.Message)
+	}
+	if len(resp.Candidates) == 0 {
+		return nil, errors.New("no response from Gemini")
+	}
+
+	var content string
+	var toolCalls []core.ToolCall
+	for _, part := range resp.Candidates[0].Content.Parts {
+		if part.Text != "" {
+			content += part.Text
+		}
+		if part.FunctionCall != nil {
+			input := make(map[string]interface{})
+			_ = json.Unmarshal(part.FunctionCall.Args, &input)
+			toolCalls = append(toolCalls, core.ToolCall{
+				ToolName: part.FunctionCall.Name,
+				Input:    input,
+			})
+		}
+	}
+
+	return &core.Response{
+		Content:   content,
+		ToolCalls: toolCalls,
+		Usage: core.Usage{
+			PromptTokens:     resp.UsageMetadata.PromptTokenCount,
+			CompletionTokens: resp.UsageMetadata.CandidatesTokenCount,
+			TotalTokens:      resp.UsageMetadata.TotalTokenCount,
+		},
+		FinishReason: resp.Candidates[0].FinishReason,
+	}, nil
+}
+
+// StreamQuery sends a query to the model and returns a stream of response chunks
+func (m *GeminiModel) StreamQuery(ctx context.Context, messages []core.Message, tools []core.Tool, opts *core.QueryOptions) (<-chan core.ResponseChunk, error) {
+	reqBody := m.buildRequest(messages, tools)
+	reqBody.GenerationConfig = geminiGenerationConfigFor(opts)
+
+	endpoint := fmt.Sprintf("%s/v1beta/models/%s:streamGenerateContent?alt=sse&key=%s", m.baseURL, m.modelName, url.QueryEscape(m.apiKey))
+
+	body, err := json.Marshal(reqBody)
+	if err != nil {
+		return nil, err
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	httpResp, err := m.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("Gemini API error: %w", err)
+	}
+	if httpResp.StatusCode != http.StatusOK {
+		defer httpResp.Body.Close()
+		return nil, fmt.Errorf("Gemini API error: unexpected status %d", httpResp.StatusCode)
+	}
+
+	responseCh := make(chan core.ResponseChunk)
+
+	go func() {
+		defer close(responseCh)
+		defer httpResp.Body.Close()
+
+		scanner := bufio.NewScanner(httpResp.Body)
+		for scanner.Scan() {
+			line := scanner.Text()
+			data, ok := strings.CutPrefix(line, "data: ")
+			if !ok {
+				continue
+			}
+
+			var chunk geminiResponse
+			if err := json.Unmarshal([]byte(data), &chunk); err != nil {
+				continue
+			}
+			if chunk.Error != nil {
+				responseCh <- core.ResponseChunk{Error: fmt.Errorf("Gemini API error: %s", chunk.Error.Message), IsDone: true}
+				return
+			}
+			if len(chunk.Candidates) == 0 {
+				continue
+			}
+			for _, part := range chunk.Candidates[0].Content.Parts {
+				if part.Text != "" {
+					responseCh <- core.ResponseChunk{Content: part.Text}
+				}
+			}
+		}
+
+		if err := scanner.Err(); err != nil {
+			responseCh <- core.ResponseChunk{Error: err, IsDone: true}
+			return
+		}
+		responseCh <- core.ResponseChunk{IsDone: true}
+	}()
+
+	return responseCh, nil
+}
+
+// buildRequest converts the session's messages and tools into a Gemini
+// generateContent request. Gemini has no "system" role in Contents, so
+// system messages become SystemInstruction; "tool" role messages (the
+// output of a prior tool call) become a functionResponse part, and
+// "assistant" maps to Gemini's "model" role.
+func (m *GeminiModel) buildRequest(messages []core.Message, tools []core.Tool) geminiRequest {
+	var req geminiRequest
+
+	for _, msg := range messages {
+		switch msg.Role {
+		case "system":
+			req.SystemInstruction = &geminiContent{Parts: []geminiPart{{Text: fmt.Sprintf("%v", msg.Content)}}}
+		case "tool":
+			response, _ := json.Marshal(map[string]interface{}{"result": msg.Content})
+			req.Contents = append(req.Contents, geminiContent{
+				Role:  "user",
+				Parts: []geminiPart{{FunctionResp: &geminiFunctionResp{Name: msg.ToolName, Response: response}}},
+			})
+		default:
+			role := msg.Role
+			if role == "assistant" {
+				role = "model"
+			}
+			var parts []geminiPart
+			if text := fmt.Sprintf("%v", msg.Content); text != "" {
+				parts = append(parts, geminiPart{Text: text})
+			}
+			for _, toolCall := range msg.ToolCalls {
+				args, _ := json.Marshal(toolCall.Input)
+				parts = append(parts, geminiPart{FunctionCall: &geminiFunctionCall{Name: toolCall.ToolName, Args: args}})
+			}
+			req.Contents = append(req.Contents, geminiContent{Role: role, Parts: parts})
+		}
+	}
+
+	for _, tool := range tools {
+		req.Tools = append(req.Tools, geminiTool{FunctionDeclarations: []geminiFunctionDeclaration{{
+			Name:        tool.Name(),
+			Description: tool.Description(),
+			Parameters:  json.RawMessage(fmt.Sprintf(`{"type":"object","properties":%s}`, tool.Arguments())),
+		}}})
+	}
+
+	return req
+}
+
+func (m *GeminiModel) doRequest(ctx context.Context, endpoint string, reqBody geminiRequest, out interface{}) error {
+	body, err := json.Marshal(reqBody)
+	if err != nil {
+		return err
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	httpResp, err := m.httpClient.Do(httpReq)
+	if err != nil {
+		return fmt.Errorf("Gemini API error: %w", err)
+	}
+	defer httpResp.Body.Close()
+
+	return json.NewDecoder(httpResp.Body).Decode(out)
+}
@@ -0,0 +1,42 @@
+package ai
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/cinience/aigo-kode/internal/core"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestGeminiBuildRequestSetsFunctionResponseName exercises a 2-round
+// tool-call conversation and asserts the functionResponse part carries the
+// tool's name - Gemini has no call-ID concept, so Name is how it pairs a
+// functionResponse back up with the functionCall that requested it.
+func TestGeminiBuildRequestSetsFunctionResponseName(t *testing.T) {
+	m := &GeminiModel{modelName: geminiDefaultModel}
+
+	messages := []core.Message{
+		{Role: "user", Content: "what's in the repo root?"},
+		{Role: "assistant", Content: "", ToolCalls: []core.ToolCall{
+			{ToolName: "LS", Input: map[string]interface{}{"path": "."}},
+		}},
+		{Role: "tool", Content: "README.md\nmain.go", ToolName: "LS"},
+		{Role: "assistant", Content: "The repo root has README.md and main.go."},
+	}
+
+	req := m.buildRequest(messages, nil)
+
+	assert.Len(t, req.Contents, 4)
+
+	call := req.Contents[1].Parts[0].FunctionCall
+	assert.NotNil(t, call)
+	assert.Equal(t, "LS", call.Name)
+
+	resp := req.Contents[2].Parts[0].FunctionResp
+	assert.NotNil(t, resp)
+	assert.Equal(t, "LS", resp.Name)
+
+	var decoded map[string]string
+	assert.NoError(t, json.Unmarshal(resp.Response, &decoded))
+	assert.Equal(t, "README.md\nmain.go", decoded["result"])
+}
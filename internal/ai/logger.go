@@ -0,0 +1,21 @@
+package ai
+
+// Logger is the structured logging interface OpenAIModel uses for
+// diagnostics (failed requests, stream errors). Callers inject their own
+// implementation - wrapping log/slog, zap, or whatever the host
+// application already uses - via OpenAIModel.SetLogger; the model falls
+// back to a no-op logger so diagnostics are opt-in rather than forced onto
+// stdout. The other provider models do not log.
+type Logger interface {
+	Debug(msg string, keysAndValues ...interface{})
+	Error(msg string, keysAndValues ...interface{})
+}
+
+type noopLogger struct{}
+
+func (noopLogger) Debug(msg string, keysAndValues ...interface{}) {}
+func (noopLogger) Error(msg string, keysAndValues ...interface{}) {}
+
+// DefaultLogger discards everything; it's what model constructors use
+// until a caller supplies its own Logger.
+var DefaultLogger Logger = noopLogger{}
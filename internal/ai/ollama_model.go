@@ -0,0 +1,277 @@
+package ai
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/cinience/aigo-kode/internal/core"
+)
+
+const (
+	ollamaDefaultBaseURL = "http://localhost:11434"
+	ollamaDefaultModel   = "llama3"
+)
+
+func init() {
+	RegisterProvider("ollama", func(cfg ProviderConfig) (core.AIModel, error) {
+		return NewOllamaModel(cfg.DefaultModel, cfg.BaseURL)
+	})
+}
+
+// OllamaModel implements the AIModel interface for a local Ollama server,
+// talking to its /api/chat endpoint. Unlike the hosted providers, Ollama
+// needs no API key.
+type OllamaModel struct {
+	httpClient *http.Client
+	baseURL    string
+	modelName  string
+}
+
+// NewOllamaModel creates a new Ollama model
+func NewOllamaModel(modelName, baseURL string) (*OllamaModel, error) {
+	if modelName == "" {
+		modelName = ollamaDefaultModel
+	}
+
+	if baseURL == "" {
+		baseURL = ollamaDefaultBaseURL
+	}
+
+	return &OllamaModel{
+		httpClient: http.DefaultClient,
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		modelName:  modelName,
+	}, nil
+}
+
+// Name returns the model name
+func (m *OllamaModel) Name() string {
+	return m.modelName
+}
+
+// Provider returns the model provider
+func (m *OllamaModel) Provider() string {
+	return "Ollama"
+}
+
+type ollamaToolCall struct {
+	Function struct {
+		Name      string          `json:"name"`
+		Arguments json.RawMessage `json:"arguments"`
+	} `json:"function"`
+}
+
+type ollamaMessage struct {
+	Role      string           `json:"role"`
+	Content   string           `json:"content"`
+	ToolCalls []ollamaToolCall `json:"tool_calls,omitempty"`
+}
+
+type ollamaFunctionDef struct {
+	Name        string          `json:"name"`
+	Description string          `json:"description,omitempty"`
+	Parameters  json.RawMessage `json:"parameters"`
+}
+
+type ollamaTool struct {
+	Type     string            `json:"type"`
+	Function ollamaFunctionDef `json:"function"`
+}
+
+type ollamaRequest struct {
+	Model    string                 `json:"model"`
+	Messages []ollamaMessage        `json:"messages"`
+	Tools    []ollamaTool           `json:"tools,omitempty"`
+	Stream   bool                   `json:"stream"`
+	Format   json.RawMessage        `json:"format,omitempty"`
+	Options  map[string]interface{} `json:"options,omitempty"`
+}
+
+// ollamaRequestOptions builds the "format"/"options" fields that constrain
+// Ollama's output. ResponseSchema maps onto Ollama's native structured-output
+// "format" field (a JSON Schema, or the literal "json"); Grammar is passed
+// through as a raw "grammar" sampling option for llama.cpp-compatible
+// servers running behind the same /api/chat endpoint.
+func ollamaRequestOptions(opts *core.QueryOptions) (format json.RawMessage, options map[string]interface{}) {
+	if opts == nil {
+		return nil, nil
+	}
+	if opts.ResponseSchema != "" {
+		format = json.RawMessage(opts.ResponseSchema)
+	}
+	if opts.Grammar != "" {
+		options = map[string]interface{}{"grammar": opts.Grammar}
+	}
+	return format, options
+}
+
+type ollamaChatResponse struct {
+	Message ollamaMessage `json:"message"`
+	Done    bool          `json:"done"`
+	Error   string        `json:"error,omitempty"`
+
+	PromptEvalCount int `json:"prompt_eval_count"`
+	EvalCount       int `json:"eval_count"`
+}
+
+// Query sends a query to the model and returns a response
+func (m *OllamaModel) Query(ctx context.Context, messages []core.Message, tools []core.Tool, opts *core.QueryOptions) (*core.Response, error) {
+	reqBody := m.buildRequest(messages, tools, false)
+	reqBody.Format, reqBody.Options = ollamaRequestOptions(opts)
+
+	var resp ollamaChatResponse
+	if err := m.doRequest(ctx, reqBody, &resp); err != nil {
+		return nil, err
+	}
+	if resp.Error != "" {
+		return nil, fmt.Errorf("Ollama API error: %s", resp.Error)
+	}
+
+	var toolCalls []core.ToolCall
+	for _, tc := range resp.Message.ToolCalls {
+		input := make(map[string]interface{})
+		_ = json.Unmarshal(tc.Function.Arguments, &input)
+		toolCalls = append(toolCalls, core.ToolCall{
+			ToolName: tc.Function.Name,
+			Input:    input,
+		})
+	}
+
+	return &core.Response{
+		Content:   resp.Message.Content,
+		ToolCalls: toolCalls,
+		Usage: core.Usage{
+			PromptTokens:     resp.PromptEvalCount,
+			CompletionTokens: resp.EvalCount,
+			TotalTokens:      resp.PromptEvalCount + resp.EvalCount,
+		},
+		FinishReason: "stop",
+	}, nil
+}
+
+// StreamQuery sends a query to the model and returns a stream of response chunks
+func (m *OllamaModel) StreamQuery(ctx context.Context, messages []core.Message, tools []core.Tool, opts *core.QueryOptions) (<-chan core.ResponseChunk, error) {
+	reqBody := m.buildRequest(messages, tools, true)
+	reqBody.Format, reqBody.Options = ollamaRequestOptions(opts)
+
+	body, err := json.Marshal(reqBody)
+	if err != nil {
+		return nil, err
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, m.baseURL+"/api/chat", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	httpResp, err := m.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("Ollama API error: %w", err)
+	}
+	if httpResp.StatusCode != http.StatusOK {
+		defer httpResp.Body.Close()
+		return nil, fmt.Errorf("Ollama API error: unexpected status %d", httpResp.StatusCode)
+	}
+
+	responseCh := make(chan core.ResponseChunk)
+
+	go func() {
+		defer close(responseCh)
+		defer httpResp.Body.Close()
+
+		// Ollama streams one JSON object per line, not SSE.
+		scanner := bufio.NewScanner(httpResp.Body)
+		for scanner.Scan() {
+			line := scanner.Bytes()
+			if len(line) == 0 {
+				continue
+			}
+
+			var chunk ollamaChatResponse
+			if err := json.Unmarshal(line, &chunk); err != nil {
+				continue
+			}
+			if chunk.Error != "" {
+				responseCh <- core.ResponseChunk{Error: errors.New(chunk.Error), IsDone: true}
+				return
+			}
+
+			responseCh <- core.ResponseChunk{Content: chunk.Message.Content, IsDone: chunk.Done}
+			if chunk.Done {
+				return
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			responseCh <- core.ResponseChunk{Error: err, IsDone: true}
+		}
+	}()
+
+	return responseCh, nil
+}
+
+// buildRequest converts the session's messages and tools into an Ollama
+// chat request. Ollama's chat API already uses role names ("system",
+// "user", "assistant", "tool") that line up with core.Message's, so no
+// remapping is needed beyond flattening Content to a string.
+func (m *OllamaModel) buildRequest(messages []core.Message, tools []core.Tool, stream bool) ollamaRequest {
+	req := ollamaRequest{
+		Model:  m.modelName,
+		Stream: stream,
+	}
+
+	for _, msg := range messages {
+		om := ollamaMessage{
+			Role:    msg.Role,
+			Content: fmt.Sprintf("%v", msg.Content),
+		}
+		for _, toolCall := range msg.ToolCalls {
+			args, _ := json.Marshal(toolCall.Input)
+			var tc ollamaToolCall
+			tc.Function.Name = toolCall.ToolName
+			tc.Function.Arguments = args
+			om.ToolCalls = append(om.ToolCalls, tc)
+		}
+		req.Messages = append(req.Messages, om)
+	}
+
+	for _, tool := range tools {
+		req.Tools = append(req.Tools, ollamaTool{
+			Type: "function",
+			Function: ollamaFunctionDef{
+				Name:        tool.Name(),
+				Description: tool.Description(),
+				Parameters:  json.RawMessage(fmt.Sprintf(`{"type":"object","properties":%s}`, tool.Arguments())),
+			},
+		})
+	}
+
+	return req
+}
+
+func (m *OllamaModel) doRequest(ctx context.Context, reqBody ollamaRequest, out interface{}) error {
+	body, err := json.Marshal(reqBody)
+	if err != nil {
+		return err
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, m.baseURL+"/api/chat", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	httpResp, err := m.httpClient.Do(httpReq)
+	if err != nil {
+		return fmt.Errorf("Ollama API error: %w", err)
+	}
+	defer httpResp.Body.Close()
+
+	return json.NewDecoder(httpResp.Body).Decode(out)
+}
@@ -5,9 +5,10 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"log"
+	"sort"
 	"strings"
 
+	"github.com/cinience/aigo-kode/internal/config"
 	"github.com/cinience/aigo-kode/internal/core"
 	"github.com/sashabaranov/go-openai"
 )
@@ -18,6 +19,21 @@ type OpenAIModel struct {
 	modelName   string
 	temperature float32
 	maxTokens   int
+	logger      Logger
+	presets     *config.ModelRegistry
+}
+
+func init() {
+	RegisterProvider("openai", func(cfg ProviderConfig) (core.AIModel, error) {
+		m, err := NewOpenAIModel(cfg.APIKey, cfg.DefaultModel, cfg.BaseURL)
+		if err != nil {
+			return nil, err
+		}
+		if cfg.Presets != nil {
+			m.SetPresets(cfg.Presets)
+		}
+		return m, nil
+	})
 }
 
 // NewOpenAIModel creates a new OpenAI model
@@ -42,9 +58,45 @@ func NewOpenAIModel(apiKey, modelName, baseURL string) (*OpenAIModel, error) {
 		modelName:   modelName,
 		temperature: 0.7,
 		maxTokens:   4096,
+		logger:      DefaultLogger,
 	}, nil
 }
 
+// SetLogger installs the logger used for request/stream diagnostics,
+// replacing the no-op default.
+func (m *OpenAIModel) SetLogger(logger Logger) {
+	if logger != nil {
+		m.logger = logger
+	}
+}
+
+// SetPresets installs the registry of model presets used to override
+// temperature/maxTokens per model alias.
+func (m *OpenAIModel) SetPresets(registry *config.ModelRegistry) {
+	m.presets = registry
+}
+
+// effectiveParams returns the temperature and maxTokens to send with a
+// request, overridden by the preset registered for m.modelName if one
+// exists and sets the corresponding parameter.
+func (m *OpenAIModel) effectiveParams() (temperature float32, maxTokens int) {
+	temperature, maxTokens = m.temperature, m.maxTokens
+	if m.presets == nil {
+		return temperature, maxTokens
+	}
+	preset, ok := m.presets.Get(m.modelName)
+	if !ok {
+		return temperature, maxTokens
+	}
+	if preset.Parameters.Temperature != nil {
+		temperature = float32(*preset.Parameters.Temperature)
+	}
+	if preset.Parameters.MaxTokens != nil {
+		maxTokens = *preset.Parameters.MaxTokens
+	}
+	return temperature, maxTokens
+}
+
 // Name returns the model name
 func (m *OpenAIModel) Name() string {
 	return m.modelName
@@ -55,9 +107,8 @@ func (m *OpenAIModel) Provider() string {
 	return "OpenAI"
 }
 
-// Query sends a query to the model and returns a response
-func (m *OpenAIModel) Query(ctx context.Context, messages []core.Message, tools []core.Tool) (*core.Response, error) {
-	// Convert messages to OpenAI format
+// buildChatMessages converts session messages to OpenAI's wire format.
+func buildChatMessages(messages []core.Message) []openai.ChatCompletionMessage {
 	openaiMessages := make([]openai.ChatCompletionMessage, len(messages))
 	for i, msg := range messages {
 		openaiMessages[i] = openai.ChatCompletionMessage{
@@ -78,17 +129,18 @@ func (m *OpenAIModel) Query(ctx context.Context, messages []core.Message, tools
 			}
 		}
 	}
+	return openaiMessages
+}
 
-	// Create request
-	req := openai.ChatCompletionRequest{
-		Model:       m.modelName,
-		Messages:    openaiMessages,
-		Temperature: m.temperature,
-		MaxTokens:   m.maxTokens,
-		Tools:       make([]openai.Tool, len(tools)),
-	}
-	for toolIdx, tool := range tools {
-		req.Tools[toolIdx] = openai.Tool{
+// buildChatTools converts the session's tools to OpenAI's function-calling
+// tool definitions.
+func buildChatTools(tools []core.Tool) []openai.Tool {
+	if len(tools) == 0 {
+		return nil
+	}
+	openaiTools := make([]openai.Tool, len(tools))
+	for i, tool := range tools {
+		openaiTools[i] = openai.Tool{
 			Type: openai.ToolTypeFunction,
 			Function: &openai.FunctionDefinition{
 				Name:       tool.Name(),
@@ -96,42 +148,62 @@ func (m *OpenAIModel) Query(ctx context.Context, messages []core.Message, tools
 			},
 		}
 	}
+	return openaiTools
+}
+
+// responseFormat builds the response_format OpenAI uses to force output
+// that conforms to opts.ResponseSchema. opts.Grammar has no OpenAI
+// equivalent and is ignored.
+func responseFormat(opts *core.QueryOptions) *openai.ChatCompletionResponseFormat {
+	if opts == nil || opts.ResponseSchema == "" {
+		return nil
+	}
+	return &openai.ChatCompletionResponseFormat{
+		Type: openai.ChatCompletionResponseFormatTypeJSONSchema,
+		JSONSchema: &openai.ChatCompletionResponseFormatJSONSchema{
+			Name:   "tool_output",
+			Schema: json.RawMessage(opts.ResponseSchema),
+			Strict: true,
+		},
+	}
+}
+
+// Query sends a query to the model and returns a response
+func (m *OpenAIModel) Query(ctx context.Context, messages []core.Message, tools []core.Tool, opts *core.QueryOptions) (*core.Response, error) {
+	temperature, maxTokens := m.effectiveParams()
+	req := openai.ChatCompletionRequest{
+		Model:          m.modelName,
+		Messages:       buildChatMessages(messages),
+		Temperature:    temperature,
+		MaxTokens:      maxTokens,
+		Tools:          buildChatTools(tools),
+		ResponseFormat: responseFormat(opts),
+	}
 
-	// Send request
 	resp, err := m.client.CreateChatCompletion(ctx, req)
 	if err != nil {
-		log.Println(err)
+		m.logger.Error("openai: chat completion request failed", "error", err)
 		return nil, fmt.Errorf("OpenAI API error: %w", err)
 	}
 
-	data, _ := json.Marshal(resp)
-	log.Println("*************************")
-	log.Println(string(data))
-	log.Println("$$$$$$$$$$$$$$$$$$$$$$$")
-	// Process response
 	if len(resp.Choices) == 0 {
 		return nil, errors.New("no response from OpenAI")
 	}
 
 	var content string
 	var finishReason string
-
-	// For simplicity, we're not handling tool calls in this version
-	// since the API seems to have changed
 	var toolCalls []core.ToolCall
 	for _, choice := range resp.Choices {
 		content = choice.Message.Content
 		finishReason = string(choice.FinishReason)
-		if choice.Message.ToolCalls != nil {
-			for _, toolCall := range choice.Message.ToolCalls {
-				input := make(map[string]interface{})
-				_ = json.Unmarshal([]byte(toolCall.Function.Arguments), &input)
-				toolCalls = append(toolCalls, core.ToolCall{
-					ID:       toolCall.ID,
-					ToolName: toolCall.Function.Name,
-					Input:    input,
-				})
-			}
+		for _, toolCall := range choice.Message.ToolCalls {
+			input := make(map[string]interface{})
+			_ = json.Unmarshal([]byte(toolCall.Function.Arguments), &input)
+			toolCalls = append(toolCalls, core.ToolCall{
+				ID:       toolCall.ID,
+				ToolName: toolCall.Function.Name,
+				Input:    input,
+			})
 		}
 	}
 
@@ -148,64 +220,155 @@ func (m *OpenAIModel) Query(ctx context.Context, messages []core.Message, tools
 }
 
 // StreamQuery sends a query to the model and returns a stream of response chunks
-func (m *OpenAIModel) StreamQuery(ctx context.Context, messages []core.Message, tools []core.Tool) (<-chan core.ResponseChunk, error) {
-	// Convert messages to OpenAI format
-	openaiMessages := make([]openai.ChatCompletionMessage, len(messages))
-	for i, msg := range messages {
-		openaiMessages[i] = openai.ChatCompletionMessage{
-			Role:    msg.Role,
-			Content: fmt.Sprintf("%v", msg.Content),
-		}
-	}
-
-	// Create request
+func (m *OpenAIModel) StreamQuery(ctx context.Context, messages []core.Message, tools []core.Tool, opts *core.QueryOptions) (<-chan core.ResponseChunk, error) {
+	temperature, maxTokens := m.effectiveParams()
 	req := openai.ChatCompletionRequest{
-		Model:       m.modelName,
-		Messages:    openaiMessages,
-		Temperature: m.temperature,
-		MaxTokens:   m.maxTokens,
-		Stream:      true,
+		Model:          m.modelName,
+		Messages:       buildChatMessages(messages),
+		Temperature:    temperature,
+		MaxTokens:      maxTokens,
+		Tools:          buildChatTools(tools),
+		ResponseFormat: responseFormat(opts),
+		Stream:         true,
+		StreamOptions: &openai.StreamOptions{
+			IncludeUsage: true,
+		},
 	}
 
-	// Send request
 	stream, err := m.client.CreateChatCompletionStream(ctx, req)
 	if err != nil {
+		m.logger.Error("openai: failed to start stream", "error", err)
 		return nil, fmt.Errorf("OpenAI API error: %w", err)
 	}
 
-	// Create response channel
 	responseCh := make(chan core.ResponseChunk)
 
-	// Process stream in a goroutine
 	go func() {
 		defer close(responseCh)
 		defer stream.Close()
 
+		accum := newToolCallAccumulator()
+		var finishReason string
+		var usage core.Usage
+
 		for {
-			response, err := stream.Recv()
+			chunk, err := stream.Recv()
 			if err != nil {
 				if strings.Contains(err.Error(), "EOF") {
-					// End of stream
 					responseCh <- core.ResponseChunk{
-						IsDone: true,
+						ToolCalls:    accum.finalize(),
+						FinishReason: finishReason,
+						Usage:        usage,
+						IsDone:       true,
 					}
 					return
 				}
-				// Other error
-				responseCh <- core.ResponseChunk{
-					Error:  err,
-					IsDone: true,
-				}
+				m.logger.Error("openai: stream error", "error", err)
+				responseCh <- core.ResponseChunk{Error: err, IsDone: true}
 				return
 			}
 
-			// Send chunk
+			if chunk.Usage != nil {
+				usage = core.Usage{
+					PromptTokens:     chunk.Usage.PromptTokens,
+					CompletionTokens: chunk.Usage.CompletionTokens,
+					TotalTokens:      chunk.Usage.TotalTokens,
+				}
+			}
+
+			if len(chunk.Choices) == 0 {
+				// The trailing usage-only chunk sent by stream_options has
+				// no choices; nothing more to forward from it.
+				continue
+			}
+
+			choice := chunk.Choices[0]
+			if choice.FinishReason != "" {
+				finishReason = string(choice.FinishReason)
+			}
+
+			var partialToolCalls []core.ToolCall
+			if len(choice.Delta.ToolCalls) > 0 {
+				partialToolCalls = accum.add(choice.Delta.ToolCalls)
+			}
+
+			if choice.Delta.Content == "" && len(partialToolCalls) == 0 {
+				continue
+			}
+
 			responseCh <- core.ResponseChunk{
-				Content: response.Choices[0].Delta.Content,
-				IsDone:  false,
+				Content:   choice.Delta.Content,
+				ToolCalls: partialToolCalls,
 			}
 		}
 	}()
 
 	return responseCh, nil
 }
+
+// toolCallAccumulator reassembles OpenAI's streamed tool-call fragments.
+// Each chunk's Delta.ToolCalls carries a fragment keyed by Index: the ID
+// and function Name normally arrive on the fragment's first appearance,
+// while Arguments arrives as a partial JSON string that must be
+// concatenated across chunks before it can be parsed.
+type toolCallAccumulator struct {
+	order []int
+	byIdx map[int]*accumulatedToolCall
+}
+
+type accumulatedToolCall struct {
+	id   string
+	name string
+	args strings.Builder
+}
+
+func newToolCallAccumulator() *toolCallAccumulator {
+	return &toolCallAccumulator{byIdx: make(map[int]*accumulatedToolCall)}
+}
+
+// add folds a chunk's tool-call fragments into the accumulator and returns
+// the partial core.ToolCall values seen so far, in the order they were
+// first introduced - best-effort parsed, since a fragment's arguments are
+// rarely valid JSON until the call is complete.
+func (a *toolCallAccumulator) add(deltas []openai.ToolCall) []core.ToolCall {
+	for _, delta := range deltas {
+		idx := 0
+		if delta.Index != nil {
+			idx = *delta.Index
+		}
+
+		call, ok := a.byIdx[idx]
+		if !ok {
+			call = &accumulatedToolCall{}
+			a.byIdx[idx] = call
+			a.order = append(a.order, idx)
+		}
+		if delta.ID != "" {
+			call.id = delta.ID
+		}
+		if delta.Function.Name != "" {
+			call.name = delta.Function.Name
+		}
+		call.args.WriteString(delta.Function.Arguments)
+	}
+
+	return a.snapshot()
+}
+
+func (a *toolCallAccumulator) snapshot() []core.ToolCall {
+	calls := make([]core.ToolCall, 0, len(a.order))
+	for _, idx := range a.order {
+		call := a.byIdx[idx]
+		input := make(map[string]interface{})
+		_ = json.Unmarshal([]byte(call.args.String()), &input)
+		calls = append(calls, core.ToolCall{ID: call.id, ToolName: call.name, Input: input})
+	}
+	return calls
+}
+
+// finalize returns the fully-accumulated tool calls once the stream has
+// ended, in ascending index order.
+func (a *toolCallAccumulator) finalize() []core.ToolCall {
+	sort.Ints(a.order)
+	return a.snapshot()
+}
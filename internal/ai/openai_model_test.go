@@ -0,0 +1,35 @@
+package ai
+
+import (
+	"testing"
+
+	"github.com/sashabaranov/go-openai"
+	"github.com/stretchr/testify/assert"
+)
+
+func intPtr(i int) *int { return &i }
+
+func TestToolCallAccumulator(t *testing.T) {
+	accum := newToolCallAccumulator()
+
+	partial := accum.add([]openai.ToolCall{
+		{Index: intPtr(0), ID: "call_1", Function: openai.FunctionCall{Name: "Bash", Arguments: `{"comm`}},
+	})
+	assert.Len(t, partial, 1)
+	assert.Equal(t, "call_1", partial[0].ID)
+	assert.Equal(t, "Bash", partial[0].ToolName)
+
+	accum.add([]openai.ToolCall{
+		{Index: intPtr(0), Function: openai.FunctionCall{Arguments: `and":"ls"}`}},
+	})
+	accum.add([]openai.ToolCall{
+		{Index: intPtr(1), ID: "call_2", Function: openai.FunctionCall{Name: "LS", Arguments: `{"path":"."}`}},
+	})
+
+	final := accum.finalize()
+	assert.Len(t, final, 2)
+	assert.Equal(t, "call_1", final[0].ID)
+	assert.Equal(t, "ls", final[0].Input["command"])
+	assert.Equal(t, "call_2", final[1].ID)
+	assert.Equal(t, ".", final[1].Input["path"])
+}
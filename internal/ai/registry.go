@@ -0,0 +1,85 @@
+package ai
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/cinience/aigo-kode/internal/config"
+	"github.com/cinience/aigo-kode/internal/core"
+)
+
+// ProviderConfig describes how to reach one AI provider: its credentials,
+// endpoint, and the model to fall back on when the caller doesn't name one
+// explicitly.
+type ProviderConfig struct {
+	// APIKey authenticates requests to the provider.
+	APIKey string
+	// BaseURL overrides the provider's default API endpoint.
+	BaseURL string
+	// DefaultModel is used when the "provider:model" identifier passed to
+	// NewModel names this provider but no model.
+	DefaultModel string
+	// Headers are extra HTTP headers for the provider, e.g. for self-hosted
+	// gateways that require custom auth headers. None of the built-in
+	// providers adds them to its requests.
+	Headers map[string]string
+	// Presets, if set, lets OpenAIModel look up per-alias Temperature and
+	// MaxTokens overrides by model name at request time. The other
+	// providers do not read it, and no provider renders preset templates.
+	Presets *config.ModelRegistry
+	// SecretAccessKey is the AWS secret access key used to SigV4-sign
+	// requests to Bedrock. Unused by every other provider; APIKey holds the
+	// corresponding access key ID.
+	SecretAccessKey string
+	// Region is the AWS region Bedrock requests are signed and sent for.
+	// Unused by every other provider.
+	Region string
+}
+
+// Factory builds an AIModel for a provider given its configuration.
+type Factory func(cfg ProviderConfig) (core.AIModel, error)
+
+var (
+	registryMu sync.RWMutex
+	registry   = make(map[string]Factory)
+)
+
+// RegisterProvider makes a provider available to NewModel under name.
+// Provider implementations call this from an init function.
+func RegisterProvider(name string, factory Factory) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	registry[name] = factory
+}
+
+// NewModel builds an AIModel from an identifier of the form
+// "provider:model" (e.g. "anthropic:claude-3-5-sonnet-latest"), looking up
+// the named provider's credentials/endpoint in providers. An identifier
+// with no "provider:" prefix is treated as an OpenAI model name, so configs
+// written before provider selection existed keep working unchanged.
+func NewModel(identifier string, providers map[string]ProviderConfig) (core.AIModel, error) {
+	providerName, modelName := splitProviderModel(identifier)
+
+	registryMu.RLock()
+	factory, ok := registry[providerName]
+	registryMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("ai: unknown provider %q", providerName)
+	}
+
+	cfg := providers[providerName]
+	if modelName != "" {
+		cfg.DefaultModel = modelName
+	}
+
+	return factory(cfg)
+}
+
+// splitProviderModel parses a "provider:model" identifier.
+func splitProviderModel(identifier string) (provider, model string) {
+	if p, m, ok := strings.Cut(identifier, ":"); ok {
+		return p, m
+	}
+	return "openai", identifier
+}
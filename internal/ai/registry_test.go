@@ -0,0 +1,39 @@
+package ai
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewModelKnownProviders(t *testing.T) {
+	for _, identifier := range []string{"openai:gpt-4o", "anthropic:claude-3-5-sonnet-latest", "gemini:gemini-1.5-pro", "ollama:llama3", "bedrock:anthropic.claude-3-5-sonnet-20241022-v2:0"} {
+		providerName, _ := splitProviderModel(identifier)
+		model, err := NewModel(identifier, map[string]ProviderConfig{
+			providerName: {APIKey: "test-key", SecretAccessKey: "test-secret"},
+		})
+
+		if providerName == "ollama" {
+			// Ollama needs no API key.
+			assert.NoError(t, err)
+		} else {
+			assert.NoError(t, err)
+		}
+		assert.NotNil(t, model)
+	}
+}
+
+func TestNewModelUnknownProvider(t *testing.T) {
+	_, err := NewModel("azure:some-model", nil)
+	assert.Error(t, err)
+}
+
+func TestSplitProviderModel(t *testing.T) {
+	provider, model := splitProviderModel("anthropic:claude-3-5-sonnet-latest")
+	assert.Equal(t, "anthropic", provider)
+	assert.Equal(t, "claude-3-5-sonnet-latest", model)
+
+	provider, model = splitProviderModel("gpt-3.5-turbo")
+	assert.Equal(t, "openai", provider)
+	assert.Equal(t, "gpt-3.5-turbo", model)
+}
@@ -5,26 +5,97 @@ import (
 	"errors"
 	"os"
 	"path/filepath"
+
+	"github.com/cinience/aigo-kode/internal/sandbox"
 )
 
 // GlobalConfig represents the global application configuration
 type GlobalConfig struct {
-	// DefaultModel is the default AI model to use
+	// DefaultModel is the default AI model to use, in "provider:model" form
+	// (e.g. "anthropic:claude-3-5-sonnet-latest"). A value with no
+	// "provider:" prefix is treated as an OpenAI model name.
 	DefaultModel string `json:"defaultModel"`
-	// APIKeys maps provider names to API keys
-	APIKeys map[string]string `json:"apiKeys"`
-	// BaseURL is the base URL for the API
-	BaseURL string `json:"baseURL"`
+	// Providers maps provider names (e.g. "openai", "anthropic", "gemini",
+	// "ollama") to the credentials and endpoint used to reach them.
+	Providers map[string]ProviderConfig `json:"providers"`
 	// HasCompletedOnboarding indicates if the user has completed onboarding
 	HasCompletedOnboarding bool `json:"hasCompletedOnboarding"`
 	// LastOnboardingVersion is the version when onboarding was last completed
 	LastOnboardingVersion string `json:"lastOnboardingVersion"`
+	// LSPServers maps an LSP language identifier (e.g. "go", "python") to
+	// the server command the code-intelligence tools should launch for it
+	LSPServers map[string]LSPServerConfig `json:"lspServers,omitempty"`
+	// MCPServers maps a server name (used to namespace its tools, e.g.
+	// "mcp_<name>_<tool>") to how to reach it.
+	MCPServers map[string]MCPServerConfig `json:"mcpServers,omitempty"`
+}
+
+// MCPServerConfig describes how to reach one MCP (Model Context Protocol)
+// server: either a command to launch as a stdio subprocess, or an HTTP
+// endpoint to call directly. Exactly one of Command or URL should be set.
+type MCPServerConfig struct {
+	// Command is the executable to launch for a stdio-transport server
+	// (e.g. "npx", with Args ["-y", "@some/mcp-server"]).
+	Command string `json:"command,omitempty"`
+	// Args are extra arguments to pass to Command.
+	Args []string `json:"args,omitempty"`
+	// Env sets extra environment variables for Command's subprocess, on
+	// top of the parent process's own environment.
+	Env map[string]string `json:"env,omitempty"`
+	// URL is the endpoint for an HTTP-transport server. Set instead of
+	// Command for a server reached over the network rather than launched
+	// locally.
+	URL string `json:"url,omitempty"`
+	// Headers are extra HTTP headers sent with every request to URL.
+	Headers map[string]string `json:"headers,omitempty"`
+}
+
+// ProviderConfig describes how to reach one AI provider.
+type ProviderConfig struct {
+	// APIKey authenticates requests to the provider.
+	APIKey string `json:"apiKey,omitempty"`
+	// BaseURL overrides the provider's default API endpoint.
+	BaseURL string `json:"baseURL,omitempty"`
+	// DefaultModel is used when DefaultModel names this provider but no
+	// model (e.g. "anthropic:" with nothing after the colon). A bare name
+	// with no colon is an OpenAI model name, not a provider.
+	DefaultModel string `json:"defaultModel,omitempty"`
+	// Headers are extra HTTP headers for the provider, e.g. for self-hosted
+	// gateways that require custom auth headers. None of the built-in AI
+	// providers adds them to its requests.
+	Headers map[string]string `json:"headers,omitempty"`
+	// SecretAccessKey is the AWS secret access key used to SigV4-sign
+	// requests to Bedrock. Unused by every other provider. APIKey holds the
+	// corresponding access key ID.
+	SecretAccessKey string `json:"secretAccessKey,omitempty"`
+	// Region is the AWS region Bedrock requests are signed and sent for.
+	// Unused by every other provider.
+	Region string `json:"region,omitempty"`
+}
+
+// LSPServerConfig describes how to launch the language server responsible
+// for one language identifier.
+type LSPServerConfig struct {
+	// Command is the executable to run (e.g. "gopls", "pyright-langserver")
+	Command string `json:"command"`
+	// Args are extra arguments to pass to Command (e.g. ["--stdio"])
+	Args []string `json:"args,omitempty"`
+	// InitializationOptions is passed verbatim as the LSP initialize
+	// request's initializationOptions
+	InitializationOptions json.RawMessage `json:"initializationOptions,omitempty"`
 }
 
 // ProjectConfig represents project-specific configuration
 type ProjectConfig struct {
-	// ApprovedTools is a list of tools approved for use in this project
-	ApprovedTools []string `json:"approvedTools"`
+	// ApprovedTools maps a tool name to the fingerprints approved "always"
+	// for it in this project (e.g. {"Bash": ["git", "npm"]}). An empty
+	// string entry approves every call of a tool that has no fingerprint
+	// (one that does not implement core.Fingerprinter); for a tool that
+	// does, it matches only calls whose fingerprint is empty.
+	ApprovedTools map[string][]string `json:"approvedTools"`
+	// BashPolicy restricts what the Bash tool may run in this project. A
+	// nil value leaves Bash at its default policy.
+	BashPolicy *sandbox.Policy `json:"bashPolicy,omitempty"`
 }
 
 // Config defines the interface for configuration management
@@ -68,8 +139,18 @@ func (c *FileConfig) GetGlobalConfig() (*GlobalConfig, error) {
 	if _, err := os.Stat(configPath); os.IsNotExist(err) {
 		// Return default config if file doesn't exist
 		return &GlobalConfig{
-			DefaultModel: "gpt-3.5-turbo",
-			APIKeys:      make(map[string]string),
+			DefaultModel: "openai:gpt-3.5-turbo",
+			Providers: map[string]ProviderConfig{
+				"openai":    {},
+				"anthropic": {},
+				"gemini":    {},
+				"ollama":    {BaseURL: "http://localhost:11434"},
+				"bedrock":   {},
+			},
+			LSPServers: map[string]LSPServerConfig{
+				"go":     {Command: "gopls", Args: []string{"serve"}},
+				"python": {Command: "pyright-langserver", Args: []string{"--stdio"}},
+			},
 		}, nil
 	}
 
@@ -100,7 +181,7 @@ func (c *FileConfig) GetProjectConfig(projectPath string) (*ProjectConfig, error
 	if _, err := os.Stat(configPath); os.IsNotExist(err) {
 		// Return default config if file doesn't exist
 		return &ProjectConfig{
-			ApprovedTools: []string{},
+			ApprovedTools: map[string][]string{},
 		}, nil
 	}
 
@@ -25,12 +25,13 @@ func TestFileConfig(t *testing.T) {
 	globalConfig, err := config.GetGlobalConfig()
 	assert.NoError(t, err)
 	assert.NotNil(t, globalConfig)
-	assert.Equal(t, "gpt-3.5-turbo", globalConfig.DefaultModel)
-	assert.NotNil(t, globalConfig.APIKeys)
+	assert.Equal(t, "openai:gpt-3.5-turbo", globalConfig.DefaultModel)
+	assert.NotNil(t, globalConfig.Providers)
+	assert.Equal(t, "gopls", globalConfig.LSPServers["go"].Command)
 
 	// Test SaveGlobalConfig
-	globalConfig.DefaultModel = "gpt-4"
-	globalConfig.APIKeys["openai"] = "test-api-key"
+	globalConfig.DefaultModel = "anthropic:claude-3-5-sonnet-latest"
+	globalConfig.Providers["anthropic"] = ProviderConfig{APIKey: "test-api-key"}
 	globalConfig.HasCompletedOnboarding = true
 	globalConfig.LastOnboardingVersion = "1.0.0"
 
@@ -44,8 +45,8 @@ func TestFileConfig(t *testing.T) {
 	// Test GetGlobalConfig with existing config file
 	loadedConfig, err := config.GetGlobalConfig()
 	assert.NoError(t, err)
-	assert.Equal(t, "gpt-4", loadedConfig.DefaultModel)
-	assert.Equal(t, "test-api-key", loadedConfig.APIKeys["openai"])
+	assert.Equal(t, "anthropic:claude-3-5-sonnet-latest", loadedConfig.DefaultModel)
+	assert.Equal(t, "test-api-key", loadedConfig.Providers["anthropic"].APIKey)
 	assert.True(t, loadedConfig.HasCompletedOnboarding)
 	assert.Equal(t, "1.0.0", loadedConfig.LastOnboardingVersion)
 
@@ -63,7 +64,7 @@ func TestFileConfig(t *testing.T) {
 	assert.Empty(t, projectConfig.ApprovedTools)
 
 	// Test SaveProjectConfig
-	projectConfig.ApprovedTools = []string{"BashTool", "FileReadTool"}
+	projectConfig.ApprovedTools = map[string][]string{"Bash": {"git", "npm"}, "FileRead": {""}}
 	err = config.SaveProjectConfig(projectDir, projectConfig)
 	assert.NoError(t, err)
 
@@ -74,7 +75,7 @@ func TestFileConfig(t *testing.T) {
 	// Test GetProjectConfig with existing config file
 	loadedProjectConfig, err := config.GetProjectConfig(projectDir)
 	assert.NoError(t, err)
-	assert.Equal(t, []string{"BashTool", "FileReadTool"}, loadedProjectConfig.ApprovedTools)
+	assert.Equal(t, map[string][]string{"Bash": {"git", "npm"}, "FileRead": {""}}, loadedProjectConfig.ApprovedTools)
 
 	// Test error cases
 	err = config.SaveGlobalConfig(nil)
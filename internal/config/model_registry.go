@@ -0,0 +1,252 @@
+package config
+
+import (
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"text/template"
+
+	"github.com/fsnotify/fsnotify"
+	"gopkg.in/yaml.v3"
+)
+
+// ModelParameters overrides the generation parameters a provider would
+// otherwise default to. Nil pointers leave the corresponding parameter at
+// the provider's own default. OpenAIModel applies Temperature and
+// MaxTokens; TopP and Stop are loaded but no provider sends them.
+type ModelParameters struct {
+	Temperature *float64 `yaml:"temperature,omitempty"`
+	TopP        *float64 `yaml:"top_p,omitempty"`
+	MaxTokens   *int     `yaml:"max_tokens,omitempty"`
+	Stop        []string `yaml:"stop,omitempty"`
+}
+
+// ModelTemplates holds Go text/template snippets for each kind of request,
+// rendered through RenderChat, RenderCompletion and RenderSystem. No
+// provider renders them into the requests it sends.
+type ModelTemplates struct {
+	Chat       string `yaml:"chat,omitempty"`
+	Completion string `yaml:"completion,omitempty"`
+	System     string `yaml:"system,omitempty"`
+}
+
+// ModelPreset is one models/*.yaml file: a logical model alias, the
+// provider/backend it runs against, and the parameters and prompt templates
+// attached to that alias (see ModelParameters and ModelTemplates for which
+// of them providers apply).
+type ModelPreset struct {
+	Name       string          `yaml:"name"`
+	Backend    string          `yaml:"backend,omitempty"`
+	BaseURL    string          `yaml:"base_url,omitempty"`
+	Parameters ModelParameters `yaml:"parameters,omitempty"`
+	Templates  ModelTemplates  `yaml:"template,omitempty"`
+
+	chatTemplate       *template.Template
+	completionTemplate *template.Template
+	systemTemplate     *template.Template
+}
+
+// compile parses the preset's non-empty template strings, so render errors
+// surface at load time rather than on the first request that hits them.
+func (p *ModelPreset) compile() error {
+	var err error
+	if p.Templates.Chat != "" {
+		if p.chatTemplate, err = template.New(p.Name + ".chat").Parse(p.Templates.Chat); err != nil {
+			return err
+		}
+	}
+	if p.Templates.Completion != "" {
+		if p.completionTemplate, err = template.New(p.Name + ".completion").Parse(p.Templates.Completion); err != nil {
+			return err
+		}
+	}
+	if p.Templates.System != "" {
+		if p.systemTemplate, err = template.New(p.Name + ".system").Parse(p.Templates.System); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RenderSystem renders the preset's system template against data. ok is
+// false if the preset defines no system template, in which case out is
+// always empty and the caller should fall back to its own default.
+func (p *ModelPreset) RenderSystem(data interface{}) (out string, ok bool, err error) {
+	return renderTemplate(p.systemTemplate, data)
+}
+
+// RenderChat renders the preset's chat template against data. See RenderSystem.
+func (p *ModelPreset) RenderChat(data interface{}) (out string, ok bool, err error) {
+	return renderTemplate(p.chatTemplate, data)
+}
+
+// RenderCompletion renders the preset's completion template against data. See RenderSystem.
+func (p *ModelPreset) RenderCompletion(data interface{}) (out string, ok bool, err error) {
+	return renderTemplate(p.completionTemplate, data)
+}
+
+func renderTemplate(tmpl *template.Template, data interface{}) (string, bool, error) {
+	if tmpl == nil {
+		return "", false, nil
+	}
+	var buf strings.Builder
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", true, err
+	}
+	return buf.String(), true, nil
+}
+
+// ModelRegistry loads model/preset YAML files from a directory and
+// hot-reloads them as files are added, changed, or removed, so operators
+// can tune model parameters and prompt templates without restarting.
+type ModelRegistry struct {
+	dir string
+
+	mu      sync.RWMutex
+	presets map[string]*ModelPreset
+
+	watcher *fsnotify.Watcher
+	done    chan struct{}
+}
+
+// NewModelRegistry loads every *.yaml/*.yml file in dir and starts watching
+// it for changes. A missing directory is not an error - it just means no
+// presets are configured yet, and the registry will pick them up if the
+// directory is created later... except fsnotify can't watch a path that
+// doesn't exist yet, so in that case watching is skipped until the next
+// process restart.
+func NewModelRegistry(dir string) (*ModelRegistry, error) {
+	r := &ModelRegistry{
+		dir:     dir,
+		presets: make(map[string]*ModelPreset),
+	}
+
+	if err := r.reload(); err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+	if err := watcher.Add(dir); err != nil {
+		watcher.Close()
+		return r, nil
+	}
+
+	r.watcher = watcher
+	r.done = make(chan struct{})
+	go r.watch()
+
+	return r, nil
+}
+
+// Get returns the preset registered under alias, if any.
+func (r *ModelRegistry) Get(alias string) (*ModelPreset, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	preset, ok := r.presets[alias]
+	return preset, ok
+}
+
+// Names returns the aliases of every currently loaded preset.
+func (r *ModelRegistry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	names := make([]string, 0, len(r.presets))
+	for name := range r.presets {
+		names = append(names, name)
+	}
+	return names
+}
+
+// Close stops the directory watcher. It is a no-op if the directory didn't
+// exist at construction time and watching never started.
+func (r *ModelRegistry) Close() error {
+	if r.watcher == nil {
+		return nil
+	}
+	close(r.done)
+	return r.watcher.Close()
+}
+
+// reload re-reads every preset file in dir, replacing the registry's
+// contents atomically so concurrent Get calls never see a half-loaded set.
+func (r *ModelRegistry) reload() error {
+	entries, err := os.ReadDir(r.dir)
+	if err != nil {
+		return err
+	}
+
+	presets := make(map[string]*ModelPreset, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		ext := strings.ToLower(filepath.Ext(entry.Name()))
+		if ext != ".yaml" && ext != ".yml" {
+			continue
+		}
+
+		preset, err := loadModelPreset(filepath.Join(r.dir, entry.Name()))
+		if err != nil {
+			return err
+		}
+		if preset.Name == "" {
+			preset.Name = strings.TrimSuffix(entry.Name(), ext)
+		}
+		presets[preset.Name] = preset
+	}
+
+	r.mu.Lock()
+	r.presets = presets
+	r.mu.Unlock()
+
+	return nil
+}
+
+func loadModelPreset(path string) (*ModelPreset, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var preset ModelPreset
+	if err := yaml.Unmarshal(data, &preset); err != nil {
+		return nil, err
+	}
+	if err := preset.compile(); err != nil {
+		return nil, err
+	}
+
+	return &preset, nil
+}
+
+// watch re-runs reload whenever a file in dir changes, logging (rather than
+// failing) a bad YAML file so one broken preset doesn't take down every
+// other alias already loaded.
+func (r *ModelRegistry) watch() {
+	for {
+		select {
+		case <-r.done:
+			return
+		case event, ok := <-r.watcher.Events:
+			if !ok {
+				return
+			}
+			if event.Op&(fsnotify.Write|fsnotify.Create|fsnotify.Remove|fsnotify.Rename) == 0 {
+				continue
+			}
+			if err := r.reload(); err != nil {
+				log.Printf("config: failed to reload model presets: %v", err)
+			}
+		case err, ok := <-r.watcher.Errors:
+			if !ok {
+				return
+			}
+			log.Printf("config: model preset watcher error: %v", err)
+		}
+	}
+}
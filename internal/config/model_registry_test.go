@@ -0,0 +1,76 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func writePresetFile(t *testing.T, dir, name, content string) {
+	t.Helper()
+	err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644)
+	assert.NoError(t, err)
+}
+
+func TestModelRegistryLoadsPresets(t *testing.T) {
+	dir, err := os.MkdirTemp("", "modelstest")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	writePresetFile(t, dir, "fast.yaml", `
+name: fast
+backend: openai
+parameters:
+  temperature: 0.2
+  max_tokens: 512
+template:
+  system: "You are {{.Persona}}, a terse coding assistant."
+`)
+
+	registry, err := NewModelRegistry(dir)
+	assert.NoError(t, err)
+	defer registry.Close()
+
+	preset, ok := registry.Get("fast")
+	assert.True(t, ok)
+	assert.Equal(t, "openai", preset.Backend)
+	assert.NotNil(t, preset.Parameters.Temperature)
+	assert.Equal(t, 0.2, *preset.Parameters.Temperature)
+	assert.NotNil(t, preset.Parameters.MaxTokens)
+	assert.Equal(t, 512, *preset.Parameters.MaxTokens)
+
+	rendered, ok, err := preset.RenderSystem(map[string]string{"Persona": "Ada"})
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "You are Ada, a terse coding assistant.", rendered)
+
+	_, ok = registry.Get("missing")
+	assert.False(t, ok)
+}
+
+func TestModelRegistryDefaultsNameToFilename(t *testing.T) {
+	dir, err := os.MkdirTemp("", "modelstest")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	writePresetFile(t, dir, "careful.yaml", `backend: anthropic`)
+
+	registry, err := NewModelRegistry(dir)
+	assert.NoError(t, err)
+	defer registry.Close()
+
+	preset, ok := registry.Get("careful")
+	assert.True(t, ok)
+	assert.Equal(t, "anthropic", preset.Backend)
+}
+
+func TestModelRegistryMissingDirIsNotAnError(t *testing.T) {
+	registry, err := NewModelRegistry(filepath.Join(os.TempDir(), "does-not-exist-kode-models"))
+	assert.NoError(t, err)
+	defer registry.Close()
+
+	_, ok := registry.Get("anything")
+	assert.False(t, ok)
+}
@@ -6,13 +6,22 @@ import (
 
 // Message represents a message in a conversation
 type Message struct {
-	// Role can be "user", "assistant", or "system"
+	// Role can be "user", "assistant", "system", or "tool"
 	Role string
 	// Content can be a string or structured content
 	Content interface{}
 
-	// ToolCalls contains any tool calls in the response
+	// ToolCalls contains any tool calls in the response. Only set on
+	// "assistant" messages.
 	ToolCalls []ToolCall
+
+	// ToolCallID is the ID of the ToolCall this message answers. Only set
+	// on "tool" messages, where it lets a provider pair the result back up
+	// with the tool_use/functionCall block that requested it.
+	ToolCallID string
+	// ToolName is the name of the tool this message's Content is the
+	// result of. Only set on "tool" messages.
+	ToolName string
 }
 
 // ToolCall represents a request from the AI to use a tool
@@ -41,8 +50,17 @@ type Usage struct {
 type ResponseChunk struct {
 	// Content is the text content of this chunk
 	Content string
-	// ToolCalls contains any tool calls in this chunk
+	// ToolCalls contains any tool calls in this chunk. Providers that
+	// stream tool-call arguments incrementally may emit the same call
+	// several times as its arguments accumulate; IsDone marks the chunk
+	// carrying the final, fully-assembled values.
 	ToolCalls []ToolCall
+	// FinishReason indicates why the model stopped generating. Only set on
+	// the terminal chunk (IsDone true).
+	FinishReason string
+	// Usage contains token usage statistics. Only set on the terminal
+	// chunk (IsDone true), once the provider has reported it.
+	Usage Usage
 	// IsDone indicates if this is the final chunk
 	IsDone bool
 	// Error contains any error that occurred
@@ -61,13 +79,32 @@ type Response struct {
 	FinishReason string
 }
 
+// QueryOptions carries optional constraints on how a model should generate
+// its response, on top of the messages and tools already in play.
+type QueryOptions struct {
+	// ResponseSchema is a JSON Schema the response content must conform to.
+	// Providers that speak an OpenAI-compatible API apply it via
+	// response_format: {"type": "json_schema", ...}; Gemini applies it via
+	// generationConfig.responseSchema. A nil *QueryOptions or an empty
+	// ResponseSchema leaves the model unconstrained.
+	ResponseSchema string
+
+	// Grammar is a GBNF grammar string constraining token sampling on
+	// llama.cpp/Ollama-style backends. Ignored by providers that only
+	// support ResponseSchema.
+	Grammar string
+}
+
 // AIModel defines the interface for AI model providers
 type AIModel interface {
-	// Query sends a query to the model and returns a response
-	Query(ctx context.Context, messages []Message, tools []Tool) (*Response, error)
+	// Query sends a query to the model and returns a response. opts may be
+	// nil, in which case the model generates unconstrained output.
+	Query(ctx context.Context, messages []Message, tools []Tool, opts *QueryOptions) (*Response, error)
 
-	// StreamQuery sends a query to the model and returns a stream of response chunks
-	StreamQuery(ctx context.Context, messages []Message, tools []Tool) (<-chan ResponseChunk, error)
+	// StreamQuery sends a query to the model and returns a stream of
+	// response chunks. opts may be nil, in which case the model generates
+	// unconstrained output.
+	StreamQuery(ctx context.Context, messages []Message, tools []Tool, opts *QueryOptions) (<-chan ResponseChunk, error)
 
 	// Name returns the model name
 	Name() string
@@ -0,0 +1,138 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// defaultRetainLastTurns is how many of the most recent user turns
+// HistoryCompactor keeps verbatim when SessionConfig.RetainLastTurns isn't
+// set.
+const defaultRetainLastTurns = 4
+
+// summarizePromptTemplate drives the side-call HistoryCompactor makes to
+// summarize messages it's about to drop. It's deliberately explicit about
+// what must survive the summary, since those are exactly the details a
+// generic summarization prompt tends to compress away.
+const summarizePromptTemplate = `Summarize the following conversation transcript. Preserve every code change, file path, and unresolved TODO mentioned - drop everything else. Write the summary as plain prose, not a transcript.
+
+%s`
+
+// HistoryCompactor keeps a session's message history within its model's
+// context window by summarizing the oldest messages once they no longer
+// fit, via a side-call to the session's own model.
+type HistoryCompactor struct {
+	// Tokenizer estimates each message's token cost. A nil Tokenizer
+	// defaults to CharEstimateTokenizer.
+	Tokenizer Tokenizer
+}
+
+// NewHistoryCompactor creates a HistoryCompactor using tokenizer, or
+// CharEstimateTokenizer if tokenizer is nil.
+func NewHistoryCompactor(tokenizer Tokenizer) *HistoryCompactor {
+	if tokenizer == nil {
+		tokenizer = CharEstimateTokenizer{}
+	}
+	return &HistoryCompactor{Tokenizer: tokenizer}
+}
+
+// Compact rewrites session.Messages in place once their estimated token
+// count exceeds session.Config.MaxContextTokens minus session.Config.MaxTokens
+// (the room the response itself needs). It always keeps the leading system
+// message(s) and the last session.Config.RetainLastTurns user turns -
+// including any tool-result messages in between - verbatim; everything
+// older is replaced with a single synthetic assistant message summarizing
+// it. A zero MaxContextTokens disables compaction entirely.
+func (c *HistoryCompactor) Compact(ctx context.Context, session *Session) error {
+	cfg := session.Config
+	if cfg == nil || cfg.MaxContextTokens == 0 {
+		return nil
+	}
+
+	budget := cfg.MaxContextTokens - cfg.MaxTokens
+	if c.totalTokens(session.Messages) <= budget {
+		return nil
+	}
+
+	retain := cfg.RetainLastTurns
+	if retain <= 0 {
+		retain = defaultRetainLastTurns
+	}
+
+	cutover := retainedCutover(session.Messages, retain)
+	if cutover <= 0 {
+		// Fewer than `retain` user turns total - there's nothing older to
+		// summarize.
+		return nil
+	}
+
+	var systemMsgs, toSummarize []Message
+	for _, msg := range session.Messages[:cutover] {
+		if msg.Role == "system" {
+			systemMsgs = append(systemMsgs, msg)
+		} else {
+			toSummarize = append(toSummarize, msg)
+		}
+	}
+	if len(toSummarize) == 0 {
+		return nil
+	}
+
+	summary, err := c.summarize(ctx, session, toSummarize)
+	if err != nil {
+		return fmt.Errorf("compacting history: %w", err)
+	}
+
+	retained := session.Messages[cutover:]
+	compacted := make([]Message, 0, len(systemMsgs)+1+len(retained))
+	compacted = append(compacted, systemMsgs...)
+	compacted = append(compacted, Message{Role: "assistant", Content: "<summary>\n" + summary + "\n</summary>"})
+	compacted = append(compacted, retained...)
+	session.Messages = compacted
+
+	return nil
+}
+
+// retainedCutover returns the index of the oldest message that belongs to
+// one of the last `retain` user turns, i.e. everything from this index
+// onward is kept verbatim. It returns 0 if messages contains fewer than
+// `retain` user turns, meaning nothing should be cut.
+func retainedCutover(messages []Message, retain int) int {
+	seen := 0
+	for i := len(messages) - 1; i >= 0; i-- {
+		if messages[i].Role != "user" {
+			continue
+		}
+		seen++
+		if seen == retain {
+			return i
+		}
+	}
+	return 0
+}
+
+// totalTokens estimates the combined token cost of messages.
+func (c *HistoryCompactor) totalTokens(messages []Message) int {
+	total := 0
+	for _, msg := range messages {
+		total += c.Tokenizer.CountTokens(messageText(msg))
+	}
+	return total
+}
+
+// summarize asks session's own model to summarize messages, via a one-off
+// query outside the session's own message history.
+func (c *HistoryCompactor) summarize(ctx context.Context, session *Session, messages []Message) (string, error) {
+	var transcript strings.Builder
+	for _, msg := range messages {
+		fmt.Fprintf(&transcript, "%s: %s\n", msg.Role, messageText(msg))
+	}
+
+	prompt := fmt.Sprintf(summarizePromptTemplate, transcript.String())
+	resp, err := session.Model.Query(ctx, []Message{{Role: "user", Content: prompt}}, nil, nil)
+	if err != nil {
+		return "", err
+	}
+	return resp.Content, nil
+}
@@ -0,0 +1,57 @@
+package core
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHistoryCompactorCompact(t *testing.T) {
+	model := &turnMockModel{finalResponse: &Response{Content: "summary of the old stuff"}}
+	session := NewSession(model, nil, &SessionConfig{
+		SystemPrompt:     "test",
+		MaxContextTokens: 50,
+		MaxTokens:        0,
+		RetainLastTurns:  1,
+	})
+
+	long := "this message is long enough to blow the token budget on its own, repeated for good measure"
+	session.Messages = append(session.Messages,
+		Message{Role: "user", Content: long},
+		Message{Role: "assistant", Content: long},
+		Message{Role: "user", Content: "the most recent question"},
+	)
+
+	err := session.Compact(context.Background())
+	assert.NoError(t, err)
+
+	assert.Equal(t, "system", session.Messages[0].Role)
+	assert.Equal(t, "assistant", session.Messages[1].Role)
+	assert.Contains(t, session.Messages[1].Content, "<summary>")
+	assert.Equal(t, "the most recent question", session.Messages[2].Content)
+}
+
+func TestHistoryCompactorNoopUnderBudget(t *testing.T) {
+	model := &turnMockModel{}
+	session := NewSession(model, nil, &SessionConfig{SystemPrompt: "test", MaxContextTokens: 100000})
+	session.AddUserMessage("hi")
+
+	original := append([]Message(nil), session.Messages...)
+	err := session.Compact(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, original, session.Messages)
+}
+
+func TestHistoryCompactorDisabledByDefault(t *testing.T) {
+	model := &turnMockModel{}
+	session := NewSession(model, nil, &SessionConfig{SystemPrompt: "test"})
+	session.AddUserMessage("hi")
+
+	original := append([]Message(nil), session.Messages...)
+	err := session.Compact(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, original, session.Messages)
+}
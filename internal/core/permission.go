@@ -0,0 +1,67 @@
+package core
+
+import "context"
+
+// PermissionDecision is how a PermissionBroker answers a pending tool
+// call.
+type PermissionDecision int
+
+const (
+	// PermissionDenied rejects the tool call.
+	PermissionDenied PermissionDecision = iota
+	// PermissionAllowedOnce allows this tool call only.
+	PermissionAllowedOnce
+	// PermissionAllowedAlways allows this tool call and every future call
+	// with the same tool+fingerprint, without prompting again.
+	PermissionAllowedAlways
+)
+
+// PermissionRequest describes one tool call awaiting approval.
+type PermissionRequest struct {
+	ToolName string
+	// Fingerprint narrows the approval below the whole tool, e.g. a
+	// binary name for Bash or a path prefix for FileWrite. Empty when the
+	// tool has no Fingerprinter, meaning the approval covers every call.
+	Fingerprint string
+	Input       map[string]interface{}
+	// IsReadOnly mirrors the tool's own IsReadOnly(), so a PermissionPolicy
+	// can tell read and write calls apart without knowing the tool itself.
+	IsReadOnly bool
+}
+
+// PermissionBroker decides whether a tool call that RequiresPermission may
+// run. Implementations range from a TUI modal prompt ("Allow BashTool to
+// run `git status`? [y/N/always]") to an HTTP server that parks the
+// request behind a pending-approval token for the frontend to resolve.
+type PermissionBroker interface {
+	// Request blocks until the call is decided or ctx is done. A broker
+	// that can't get an answer before ctx is done should return
+	// ctx.Err(), which ExecuteTool treats as a deny.
+	Request(ctx context.Context, req PermissionRequest) (PermissionDecision, error)
+}
+
+// Fingerprinter lets a tool narrow its permission approvals below the
+// whole-tool level, so approving one invocation doesn't blanket-approve
+// every use of the tool. Tools that don't implement it are approved (and
+// re-prompted) at the whole-tool level.
+type Fingerprinter interface {
+	Fingerprint(input map[string]interface{}) string
+}
+
+func fingerprintFor(tool Tool, input map[string]interface{}) string {
+	if fp, ok := tool.(Fingerprinter); ok {
+		return fp.Fingerprint(input)
+	}
+	return ""
+}
+
+// PermissionPolicy is consulted by ExecuteTool before the PermissionBroker,
+// letting a session enforce rules that don't need a human in the loop -
+// e.g. confining filesystem tools to a workspace root, or refusing every
+// write outright in a read-only session. A policy with no opinion on a
+// request defers to the PermissionBroker (and its remembered Approvals) by
+// returning handled=false; the PermissionBroker remains the layer that
+// actually prompts interactively and remembers the choice.
+type PermissionPolicy interface {
+	Evaluate(req PermissionRequest) (decision PermissionDecision, handled bool)
+}
@@ -0,0 +1,121 @@
+package core
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// PathPolicy confines filesystem tool calls to a workspace root, denying
+// any file_path that escapes it - whether via ".." segments or a symlink
+// that resolves outside Root - along with any path matching a DenyGlob.
+// It has no opinion on tool calls whose input carries no "file_path".
+type PathPolicy struct {
+	// Root is the workspace directory file_path must stay within. Empty
+	// disables the containment check (only DenyGlobs/AllowGlobs apply).
+	Root string
+	// DenyGlobs are filepath.Match patterns matched against file_path that
+	// are always denied, even inside Root.
+	DenyGlobs []string
+	// AllowGlobs, if set, restricts file_path to those matching at least
+	// one pattern, in addition to the Root containment check.
+	AllowGlobs []string
+}
+
+// Evaluate implements PermissionPolicy.
+func (p *PathPolicy) Evaluate(req PermissionRequest) (PermissionDecision, bool) {
+	filePath, ok := req.Input["file_path"].(string)
+	if !ok || filePath == "" {
+		return PermissionDenied, false
+	}
+
+	for _, pattern := range p.DenyGlobs {
+		if matched, _ := filepath.Match(pattern, filePath); matched {
+			return PermissionDenied, true
+		}
+	}
+
+	if len(p.AllowGlobs) > 0 {
+		allowed := false
+		for _, pattern := range p.AllowGlobs {
+			if matched, _ := filepath.Match(pattern, filePath); matched {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			return PermissionDenied, true
+		}
+	}
+
+	if p.Root != "" && !p.contains(filePath) {
+		return PermissionDenied, true
+	}
+
+	return PermissionDenied, false
+}
+
+// contains reports whether filePath resolves - following symlinks as far
+// as they exist - to somewhere inside Root.
+func (p *PathPolicy) contains(filePath string) bool {
+	root, err := filepath.Abs(p.Root)
+	if err != nil {
+		return false
+	}
+	root = resolveSymlinks(root)
+
+	abs := filePath
+	if !filepath.IsAbs(abs) {
+		abs = filepath.Join(root, filePath)
+	}
+	abs = resolveSymlinks(abs)
+
+	rel, err := filepath.Rel(root, abs)
+	if err != nil {
+		return false
+	}
+	return rel == "." || !strings.HasPrefix(rel, "..")
+}
+
+// resolveSymlinks follows symlinks in path as far as they exist. A path
+// (or path prefix, such as a file FileWrite is about to create) that
+// doesn't exist yet resolves its deepest existing ancestor and rejoins
+// the rest of the path verbatim.
+func resolveSymlinks(path string) string {
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		return resolved
+	}
+
+	dir, base := filepath.Split(path)
+	dir = strings.TrimSuffix(dir, string(filepath.Separator))
+	if dir == "" || dir == path {
+		return path
+	}
+	return filepath.Join(resolveSymlinks(dir), base)
+}
+
+// ReadOnlyPolicy denies every tool call except read-only ones, turning a
+// session read-only without reconfiguring every individual tool.
+type ReadOnlyPolicy struct{}
+
+// Evaluate implements PermissionPolicy.
+func (ReadOnlyPolicy) Evaluate(req PermissionRequest) (PermissionDecision, bool) {
+	if req.IsReadOnly {
+		return PermissionDenied, false
+	}
+	return PermissionDenied, true
+}
+
+// PolicyChain evaluates multiple policies in order, returning the first
+// one that has an opinion (handled=true) - so, say, a ReadOnlyPolicy and a
+// PathPolicy can both apply to the same session.
+type PolicyChain []PermissionPolicy
+
+// Evaluate implements PermissionPolicy.
+func (c PolicyChain) Evaluate(req PermissionRequest) (PermissionDecision, bool) {
+	for _, policy := range c {
+		if decision, handled := policy.Evaluate(req); handled {
+			return decision, handled
+		}
+	}
+	return PermissionDenied, false
+}
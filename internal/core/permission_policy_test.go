@@ -0,0 +1,113 @@
+package core
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPathPolicyContainment(t *testing.T) {
+	root := t.TempDir()
+	policy := &PathPolicy{Root: root}
+
+	_, handled := policy.Evaluate(PermissionRequest{
+		Input: map[string]interface{}{"file_path": filepath.Join(root, "a.txt")},
+	})
+	assert.False(t, handled)
+
+	decision, handled := policy.Evaluate(PermissionRequest{
+		Input: map[string]interface{}{"file_path": filepath.Join(root, "..", "escape.txt")},
+	})
+	assert.True(t, handled)
+	assert.Equal(t, PermissionDenied, decision)
+}
+
+func TestPathPolicyDenyGlob(t *testing.T) {
+	root := t.TempDir()
+	policy := &PathPolicy{Root: root, DenyGlobs: []string{filepath.Join(root, "*.secret")}}
+
+	decision, handled := policy.Evaluate(PermissionRequest{
+		Input: map[string]interface{}{"file_path": filepath.Join(root, "keys.secret")},
+	})
+	assert.True(t, handled)
+	assert.Equal(t, PermissionDenied, decision)
+
+	_, handled = policy.Evaluate(PermissionRequest{
+		Input: map[string]interface{}{"file_path": filepath.Join(root, "notes.txt")},
+	})
+	assert.False(t, handled)
+}
+
+func TestPathPolicyIgnoresRequestsWithoutFilePath(t *testing.T) {
+	policy := &PathPolicy{Root: t.TempDir()}
+	_, handled := policy.Evaluate(PermissionRequest{Input: map[string]interface{}{}})
+	assert.False(t, handled)
+}
+
+func TestPathPolicyFollowsSymlinkEscape(t *testing.T) {
+	root := t.TempDir()
+	outside := t.TempDir()
+
+	link := filepath.Join(root, "link")
+	if err := os.Symlink(outside, link); err != nil {
+		t.Skipf("symlinks unsupported: %v", err)
+	}
+
+	policy := &PathPolicy{Root: root}
+	decision, handled := policy.Evaluate(PermissionRequest{
+		Input: map[string]interface{}{"file_path": filepath.Join(link, "escape.txt")},
+	})
+	assert.True(t, handled)
+	assert.Equal(t, PermissionDenied, decision)
+}
+
+func TestReadOnlyPolicy(t *testing.T) {
+	policy := ReadOnlyPolicy{}
+
+	decision, handled := policy.Evaluate(PermissionRequest{IsReadOnly: false})
+	assert.True(t, handled)
+	assert.Equal(t, PermissionDenied, decision)
+
+	_, handled = policy.Evaluate(PermissionRequest{IsReadOnly: true})
+	assert.False(t, handled)
+}
+
+func TestPolicyChainDefersUntilOneHandles(t *testing.T) {
+	chain := PolicyChain{ReadOnlyPolicy{}, &PathPolicy{Root: t.TempDir()}}
+
+	decision, handled := chain.Evaluate(PermissionRequest{IsReadOnly: false})
+	assert.True(t, handled)
+	assert.Equal(t, PermissionDenied, decision)
+
+	_, handled = chain.Evaluate(PermissionRequest{IsReadOnly: true, Input: map[string]interface{}{}})
+	assert.False(t, handled)
+}
+
+type stubPolicy struct {
+	decision PermissionDecision
+	handled  bool
+}
+
+func (p stubPolicy) Evaluate(req PermissionRequest) (PermissionDecision, bool) {
+	return p.decision, p.handled
+}
+
+func TestSessionChecksPermissionPolicyBeforeBroker(t *testing.T) {
+	tool := &MockTool{name: "FileWrite", readOnly: false}
+	session := NewSession(nil, []Tool{tool}, &SessionConfig{})
+	session.PermissionPolicy = stubPolicy{decision: PermissionAllowedAlways, handled: true}
+
+	err := session.checkPermission(context.Background(), tool, ToolCall{ToolName: "FileWrite", Input: map[string]interface{}{}})
+	assert.NoError(t, err)
+	assert.True(t, session.Approvals["FileWrite"][""])
+
+	deniedTool := &MockTool{name: "Bash", readOnly: false}
+	session2 := NewSession(nil, []Tool{deniedTool}, &SessionConfig{})
+	session2.PermissionPolicy = stubPolicy{decision: PermissionDenied, handled: true}
+
+	err = session2.checkPermission(context.Background(), deniedTool, ToolCall{ToolName: "Bash", Input: map[string]interface{}{}})
+	assert.ErrorIs(t, err, ErrPermissionDenied)
+}
@@ -3,8 +3,16 @@ package core
 import (
 	"context"
 	"errors"
+	"time"
+
+	"github.com/cinience/aigo-kode/internal/sandbox"
+	"github.com/cinience/aigo-kode/internal/tools/schema"
 )
 
+// defaultPermissionTimeout bounds how long ExecuteTool waits on a
+// PermissionBroker before treating the request as denied.
+const defaultPermissionTimeout = 60 * time.Second
+
 // Session represents an interactive session with an AI model
 type Session struct {
 	// Messages is the conversation history
@@ -15,6 +23,26 @@ type Session struct {
 	Tools []Tool
 	// Config is the session configuration
 	Config *SessionConfig
+	// PermissionBroker decides whether a tool call that RequiresPermission
+	// may run. A nil broker denies every such call unless
+	// Config.SkipPermissions is set.
+	PermissionBroker PermissionBroker
+	// PermissionPolicy, if set, is consulted before PermissionBroker and
+	// can short-circuit the decision outright (e.g. a PathPolicy denying a
+	// write outside the workspace). A nil policy, or one that defers on a
+	// given request, leaves the decision to PermissionBroker as before.
+	PermissionPolicy PermissionPolicy
+	// Approvals records tool+fingerprint pairs the broker has already
+	// allowed always, keyed by tool name then fingerprint, so the broker
+	// isn't asked twice for the same call shape within this session.
+	Approvals map[string]map[string]bool
+	// OnApprovalGranted, if set, is called whenever the broker returns
+	// PermissionAllowedAlways, so the caller can persist the approval (e.g.
+	// into ProjectConfig.ApprovedTools) beyond this session's lifetime.
+	OnApprovalGranted func(toolName, fingerprint string)
+	// Tokenizer estimates token counts for Compact. A nil Tokenizer
+	// defaults to CharEstimateTokenizer.
+	Tokenizer Tokenizer
 }
 
 // SessionConfig contains configuration for a session
@@ -27,6 +55,27 @@ type SessionConfig struct {
 	MaxTokens int
 	// Temperature controls randomness (0.0-2.0)
 	Temperature float64
+	// BashPolicy records the policy the Bash tool in this session's tool
+	// registry runs under. Session does not read it: the caller that builds
+	// the registry hands the policy to the Bash tool itself, and nothing
+	// merges it with another policy.
+	BashPolicy *sandbox.Policy
+	// SkipPermissions bypasses the PermissionBroker entirely, allowing
+	// every tool call. Set from the CLI's --dangerously-skip-permissions
+	// flag; never enable it by default.
+	SkipPermissions bool
+	// PermissionTimeout bounds how long ExecuteTool waits on the
+	// PermissionBroker before treating the request as denied. Zero means
+	// defaultPermissionTimeout.
+	PermissionTimeout time.Duration
+	// MaxContextTokens is the model's total context window. Zero disables
+	// automatic history compaction; Query and StreamQuery call Compact
+	// before every request once it's set.
+	MaxContextTokens int
+	// RetainLastTurns is how many of the most recent user turns (and their
+	// assistant/tool messages) Compact always keeps verbatim. Zero means
+	// defaultRetainLastTurns.
+	RetainLastTurns int
 }
 
 // NewSession creates a new session with the given model and tools
@@ -46,9 +95,10 @@ func NewSession(model AIModel, tools []Tool, config *SessionConfig) *Session {
 				Content: config.SystemPrompt,
 			},
 		},
-		Model:  model,
-		Tools:  tools,
-		Config: config,
+		Model:     model,
+		Tools:     tools,
+		Config:    config,
+		Approvals: make(map[string]map[string]bool),
 	}
 }
 
@@ -60,40 +110,77 @@ func (s *Session) AddUserMessage(content string) {
 	})
 }
 
-// AddAssistantMessage adds an assistant message to the conversation
-func (s *Session) AddAssistantMessage(content string) {
+// AddAssistantMessage adds an assistant message to the conversation, along
+// with any tool calls it requested.
+func (s *Session) AddAssistantMessage(content string, toolCalls ...ToolCall) {
 	s.Messages = append(s.Messages, Message{
-		Role:    "assistant",
-		Content: content,
+		Role:      "assistant",
+		Content:   content,
+		ToolCalls: toolCalls,
 	})
 }
 
-// AddToolResult adds a tool result to the conversation
+// Compact summarizes the oldest messages in the conversation if they no
+// longer fit in Config.MaxContextTokens, via a HistoryCompactor. It's a
+// no-op when Config.MaxContextTokens is zero; Query and StreamQuery already
+// call it before every request, so most callers never need to call it
+// directly - it's exposed for a "/compact" command that compacts on
+// demand.
+func (s *Session) Compact(ctx context.Context) error {
+	if s.Model == nil {
+		return errors.New("no model configured")
+	}
+	return NewHistoryCompactor(s.Tokenizer).Compact(ctx, s)
+}
+
+// AddToolResult adds a tool result to the conversation, tagged with the
+// tool's name and the ID of the ToolCall it answers so providers that
+// require pairing (e.g. Anthropic's tool_use_id) can reconstruct it.
 func (s *Session) AddToolResult(toolName string, id string, result interface{}) {
-	// In a real implementation, this would format the result properly
-	// based on the specific tool and result type
 	s.Messages = append(s.Messages, Message{
-		Role:    "tool",
-		Content: result,
+		Role:       "tool",
+		Content:    result,
+		ToolCallID: id,
+		ToolName:   toolName,
 	})
 }
 
 // Query sends the current conversation to the model and returns a response
 func (s *Session) Query(ctx context.Context) (*Response, error) {
+	return s.QueryWithOptions(ctx, nil)
+}
+
+// QueryWithOptions is like Query but lets the caller constrain the response
+// to a JSON schema or grammar via opts.
+func (s *Session) QueryWithOptions(ctx context.Context, opts *QueryOptions) (*Response, error) {
 	if s.Model == nil {
 		return nil, errors.New("no model configured")
 	}
 
-	return s.Model.Query(ctx, s.Messages, s.Tools)
+	if err := s.Compact(ctx); err != nil {
+		return nil, err
+	}
+
+	return s.Model.Query(ctx, s.Messages, s.Tools, opts)
 }
 
 // StreamQuery sends the current conversation to the model and returns a stream of response chunks
 func (s *Session) StreamQuery(ctx context.Context) (<-chan ResponseChunk, error) {
+	return s.StreamQueryWithOptions(ctx, nil)
+}
+
+// StreamQueryWithOptions is like StreamQuery but lets the caller constrain
+// the response to a JSON schema or grammar via opts.
+func (s *Session) StreamQueryWithOptions(ctx context.Context, opts *QueryOptions) (<-chan ResponseChunk, error) {
 	if s.Model == nil {
 		return nil, errors.New("no model configured")
 	}
 
-	return s.Model.StreamQuery(ctx, s.Messages, s.Tools)
+	if err := s.Compact(ctx); err != nil {
+		return nil, err
+	}
+
+	return s.Model.StreamQuery(ctx, s.Messages, s.Tools, opts)
 }
 
 // ExecuteTool executes a tool and adds the result to the conversation
@@ -112,12 +199,26 @@ func (s *Session) ExecuteTool(ctx context.Context, toolCall ToolCall) (*ToolUseR
 	}
 
 	// Check if tool requires permission
-	if tool.RequiresPermission(toolCall.Input) {
-		// In a real implementation, this would prompt the user for permission
-		// For now, we'll just allow it
+	if tool.RequiresPermission(toolCall.Input) && !s.Config.SkipPermissions {
+		if err := s.checkPermission(ctx, tool, toolCall); err != nil {
+			return &ToolUseResult{
+				ToolName: toolCall.ToolName,
+				Input:    toolCall.Input,
+				Error:    err,
+			}, nil
+		}
+	}
+
+	// Validate input against the tool's declared JSON Schema before its own
+	// (possibly domain-specific) validation runs
+	if err := schema.ValidateFragment(tool.Arguments(), toolCall.Input); err != nil {
+		return &ToolUseResult{
+			ToolName: toolCall.ToolName,
+			Input:    toolCall.Input,
+			Error:    err,
+		}, nil
 	}
 
-	// Validate input
 	if err := tool.ValidateInput(toolCall.Input); err != nil {
 		return &ToolUseResult{
 			ToolName: toolCall.ToolName,
@@ -140,3 +241,72 @@ func (s *Session) ExecuteTool(ctx context.Context, toolCall ToolCall) (*ToolUseR
 
 	return result, nil
 }
+
+// checkPermission consults s.Approvals, then s.PermissionPolicy, then
+// s.PermissionBroker to decide whether toolCall may proceed. It returns
+// ErrPermissionDenied if the call is denied, times out, or there's neither
+// a policy nor a broker to ask.
+func (s *Session) checkPermission(ctx context.Context, tool Tool, toolCall ToolCall) error {
+	fingerprint := fingerprintFor(tool, toolCall.Input)
+
+	if s.Approvals[toolCall.ToolName][fingerprint] {
+		return nil
+	}
+
+	req := PermissionRequest{
+		ToolName:    toolCall.ToolName,
+		Fingerprint: fingerprint,
+		Input:       toolCall.Input,
+		IsReadOnly:  tool.IsReadOnly(),
+	}
+
+	if s.PermissionPolicy != nil {
+		if decision, handled := s.PermissionPolicy.Evaluate(req); handled {
+			if decision == PermissionDenied {
+				return ErrPermissionDenied
+			}
+			if decision == PermissionAllowedAlways {
+				s.rememberApproval(toolCall.ToolName, fingerprint)
+			}
+			return nil
+		}
+	}
+
+	if s.PermissionBroker == nil {
+		return ErrPermissionDenied
+	}
+
+	timeout := s.Config.PermissionTimeout
+	if timeout <= 0 {
+		timeout = defaultPermissionTimeout
+	}
+	reqCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	decision, err := s.PermissionBroker.Request(reqCtx, req)
+	if err != nil || decision == PermissionDenied {
+		return ErrPermissionDenied
+	}
+
+	if decision == PermissionAllowedAlways {
+		s.rememberApproval(toolCall.ToolName, fingerprint)
+	}
+
+	return nil
+}
+
+// rememberApproval records that toolName+fingerprint has been allowed
+// always, so neither PermissionPolicy nor PermissionBroker is consulted
+// again for the same call shape within this session.
+func (s *Session) rememberApproval(toolName, fingerprint string) {
+	if s.Approvals == nil {
+		s.Approvals = make(map[string]map[string]bool)
+	}
+	if s.Approvals[toolName] == nil {
+		s.Approvals[toolName] = make(map[string]bool)
+	}
+	s.Approvals[toolName][fingerprint] = true
+	if s.OnApprovalGranted != nil {
+		s.OnApprovalGranted(toolName, fingerprint)
+	}
+}
@@ -0,0 +1,140 @@
+package core
+
+import (
+	"context"
+	"sync"
+)
+
+// StreamEventType identifies the kind of incremental event produced by a
+// StreamingToolDispatcher.
+type StreamEventType string
+
+const (
+	// StreamEventTextDelta carries a chunk of assistant text as it arrives.
+	StreamEventTextDelta StreamEventType = "text_delta"
+	// StreamEventToolCallStarted is emitted just before a fully-assembled
+	// tool call is handed to a worker for execution.
+	StreamEventToolCallStarted StreamEventType = "tool_call_started"
+	// StreamEventToolResult is emitted once a single tool call has finished
+	// executing, carrying its outcome.
+	StreamEventToolResult StreamEventType = "tool_result"
+	// StreamEventToolCallCompleted is emitted once, after every tool call
+	// from the current round has finished - a barrier a consumer can wait
+	// on before deciding whether to query the model again.
+	StreamEventToolCallCompleted StreamEventType = "tool_call_completed"
+)
+
+// StreamEvent is a single incremental event produced by
+// StreamingToolDispatcher.Dispatch.
+type StreamEvent struct {
+	Type StreamEventType
+	// Content holds the text for StreamEventTextDelta.
+	Content string
+	// ToolCall holds the call for StreamEventToolCallStarted and
+	// StreamEventToolResult.
+	ToolCall *ToolCall
+	// Result holds the outcome for StreamEventToolResult.
+	Result *ToolUseResult
+}
+
+// StreamingToolDispatcher consumes a Session's StreamQuery chunk channel,
+// relying on the AIModel implementation to have already reassembled each
+// provider's incrementally-streamed function-call arguments into the
+// terminal chunk's complete ToolCalls (see ai.toolCallAccumulator). Its own
+// job is dispatch: recognizing that terminal chunk, running the tool calls
+// it carries - optionally in parallel - and reporting progress as
+// StreamEvents so the TUI and HTTP server can render it identically.
+type StreamingToolDispatcher struct {
+	// Session is used to execute each recognized tool call.
+	Session *Session
+	// Concurrency caps how many tool calls from one round run at once.
+	// Values <= 1 run them serially, in the order the stream delivered
+	// them.
+	Concurrency int
+}
+
+// NewStreamingToolDispatcher creates a dispatcher bound to session, running
+// up to concurrency tool calls from a single round in parallel.
+func NewStreamingToolDispatcher(session *Session, concurrency int) *StreamingToolDispatcher {
+	return &StreamingToolDispatcher{Session: session, Concurrency: concurrency}
+}
+
+// Dispatch reads chunks until it closes, forwarding text as
+// StreamEventTextDelta and, once the terminal chunk reports the round's
+// tool calls, executing them through d.Session and emitting
+// StreamEventToolCallStarted/StreamEventToolResult for each plus a final
+// StreamEventToolCallCompleted once they've all finished. events is closed
+// before Dispatch returns. It returns the concatenated text content, the
+// results of any tool calls (in the order the stream introduced them), and
+// the terminal chunk's FinishReason.
+func (d *StreamingToolDispatcher) Dispatch(ctx context.Context, chunks <-chan ResponseChunk, events chan<- StreamEvent) (content string, results []*ToolUseResult, finishReason string, err error) {
+	defer close(events)
+
+	var toolCalls []ToolCall
+	for chunk := range chunks {
+		if chunk.Error != nil {
+			return content, nil, finishReason, chunk.Error
+		}
+
+		if chunk.Content != "" {
+			content += chunk.Content
+			events <- StreamEvent{Type: StreamEventTextDelta, Content: chunk.Content}
+		}
+
+		if chunk.IsDone {
+			toolCalls = chunk.ToolCalls
+			finishReason = chunk.FinishReason
+		}
+	}
+
+	if len(toolCalls) == 0 {
+		return content, nil, finishReason, nil
+	}
+
+	results, err = d.runToolCalls(ctx, toolCalls, events)
+	events <- StreamEvent{Type: StreamEventToolCallCompleted}
+	return content, results, finishReason, err
+}
+
+// runToolCalls executes calls through d.Session, fanning out across up to
+// d.Concurrency workers, and returns their results in calls' original
+// order.
+func (d *StreamingToolDispatcher) runToolCalls(ctx context.Context, calls []ToolCall, events chan<- StreamEvent) ([]*ToolUseResult, error) {
+	concurrency := d.Concurrency
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	results := make([]*ToolUseResult, len(calls))
+	errs := make([]error, len(calls))
+
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+
+	for i, call := range calls {
+		i, call := i, call
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			events <- StreamEvent{Type: StreamEventToolCallStarted, ToolCall: &call}
+
+			result, err := d.Session.ExecuteTool(ctx, call)
+			results[i] = result
+			errs[i] = err
+
+			events <- StreamEvent{Type: StreamEventToolResult, ToolCall: &call, Result: result}
+		}()
+	}
+
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return results, err
+		}
+	}
+	return results, nil
+}
@@ -0,0 +1,68 @@
+package core
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStreamingToolDispatcherDispatch(t *testing.T) {
+	tool := &MockTool{name: "MockTool", description: "mock", readOnly: true}
+	session := NewSession(&turnMockModel{}, []Tool{tool}, &SessionConfig{SystemPrompt: "test"})
+
+	chunks := make(chan ResponseChunk, 3)
+	chunks <- ResponseChunk{Content: "hel"}
+	chunks <- ResponseChunk{Content: "lo"}
+	chunks <- ResponseChunk{
+		ToolCalls:    []ToolCall{{ID: "1", ToolName: "MockTool", Input: map[string]interface{}{}}},
+		FinishReason: "tool_calls",
+		IsDone:       true,
+	}
+	close(chunks)
+
+	dispatcher := NewStreamingToolDispatcher(session, 1)
+	events := make(chan StreamEvent)
+
+	var seen []StreamEventType
+	done := make(chan struct{})
+	go func() {
+		for evt := range events {
+			seen = append(seen, evt.Type)
+		}
+		close(done)
+	}()
+
+	content, results, finishReason, err := dispatcher.Dispatch(context.Background(), chunks, events)
+	<-done
+
+	assert.NoError(t, err)
+	assert.Equal(t, "hello", content)
+	assert.Equal(t, "tool_calls", finishReason)
+	assert.Len(t, results, 1)
+	assert.Equal(t,
+		[]StreamEventType{StreamEventTextDelta, StreamEventTextDelta, StreamEventToolCallStarted, StreamEventToolResult, StreamEventToolCallCompleted},
+		seen,
+	)
+}
+
+func TestStreamingToolDispatcherNoToolCalls(t *testing.T) {
+	session := NewSession(&turnMockModel{}, nil, &SessionConfig{SystemPrompt: "test"})
+
+	chunks := make(chan ResponseChunk, 1)
+	chunks <- ResponseChunk{Content: "hi", IsDone: true}
+	close(chunks)
+
+	dispatcher := NewStreamingToolDispatcher(session, 4)
+	events := make(chan StreamEvent)
+	go func() {
+		for range events {
+		}
+	}()
+
+	content, results, _, err := dispatcher.Dispatch(context.Background(), chunks, events)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "hi", content)
+	assert.Nil(t, results)
+}
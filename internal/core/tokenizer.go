@@ -0,0 +1,44 @@
+package core
+
+import "encoding/json"
+
+// Tokenizer estimates how many tokens a piece of text will consume once
+// sent to a model. Different providers tokenize differently, so
+// HistoryCompactor takes one as a dependency rather than hard-coding a
+// single scheme.
+type Tokenizer interface {
+	CountTokens(text string) int
+}
+
+// CharEstimateTokenizer approximates token count as roughly one token per
+// four characters, the common rule of thumb for English text on
+// BPE-tokenized models. It needs no model-specific vocabulary, so it's the
+// default for providers (or tests) that don't plug in something more
+// accurate - e.g. a tiktoken-style BPE tokenizer for OpenAI models, which
+// can implement the same interface with an exact encoding.
+type CharEstimateTokenizer struct{}
+
+// CountTokens estimates the token count of text.
+func (CharEstimateTokenizer) CountTokens(text string) int {
+	if text == "" {
+		return 0
+	}
+	tokens := len(text) / 4
+	if tokens == 0 {
+		tokens = 1
+	}
+	return tokens
+}
+
+// messageText renders a Message's content as the string a Tokenizer can
+// count, since Content may be a plain string or a structured tool result.
+func messageText(msg Message) string {
+	if s, ok := msg.Content.(string); ok {
+		return s
+	}
+	data, err := json.Marshal(msg.Content)
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
@@ -15,6 +15,12 @@ type Tool interface {
 
 	Arguments() string
 
+	// OutputSchema returns a JSON Schema describing the shape of Execute's
+	// result, so a model can be asked (via QueryOptions.ResponseSchema) to
+	// produce directly-parseable structured output instead of free text.
+	// Returns "" if the tool's output has no fixed shape worth declaring.
+	OutputSchema() string
+
 	// Execute executes the tool and returns the result
 	Execute(ctx context.Context, input map[string]interface{}) (interface{}, error)
 
@@ -1,6 +1,7 @@
 package core
 
 import (
+	"context"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -37,7 +38,7 @@ func (t *MockTool) Description() string {
 	return t.description
 }
 
-func (t *MockTool) Execute(ctx interface{}, input map[string]interface{}) (interface{}, error) {
+func (t *MockTool) Execute(ctx context.Context, input map[string]interface{}) (interface{}, error) {
 	if t.executeFunc != nil {
 		return t.executeFunc(input)
 	}
@@ -48,6 +49,14 @@ func (t *MockTool) ValidateInput(input map[string]interface{}) error {
 	return nil
 }
 
+func (t *MockTool) Arguments() string {
+	return ""
+}
+
+func (t *MockTool) OutputSchema() string {
+	return ""
+}
+
 func (t *MockTool) IsReadOnly() bool {
 	return t.readOnly
 }
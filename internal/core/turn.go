@@ -0,0 +1,85 @@
+package core
+
+import (
+	"context"
+)
+
+// EventType identifies the kind of incremental event produced while a turn
+// is being processed.
+type EventType string
+
+const (
+	// EventToken carries a chunk of assistant text as it becomes available.
+	EventToken EventType = "token"
+	// EventToolCallStarted is emitted just before a tool call is executed.
+	EventToolCallStarted EventType = "tool_call_started"
+	// EventToolCallResult is emitted once a tool call has finished executing.
+	EventToolCallResult EventType = "tool_call_result"
+	// EventDone is emitted once, as the final event of a turn.
+	EventDone EventType = "done"
+)
+
+// TurnEvent is a single incremental event produced by RunTurn. Consumers
+// (the TUI, the SSE handler) range over the channel until it closes.
+type TurnEvent struct {
+	Type EventType
+	// Content holds the text for EventToken.
+	Content string
+	// ToolCall holds the call for EventToolCallStarted.
+	ToolCall *ToolCall
+	// Result holds the outcome for EventToolCallResult.
+	Result *ToolUseResult
+	// Err holds any error that ended the turn early.
+	Err error
+}
+
+// RunTurn drives one full user turn against the model: it queries for a
+// response, executes any requested tool calls, queries again for the
+// follow-up response, and appends the final assistant message to the
+// session - emitting a TurnEvent on events as each step happens instead of
+// making the caller wait for the whole turn to finish. The channel is
+// always closed before RunTurn returns, with EventDone as its last event.
+//
+// RunTurn calls Query rather than StreamQuery, so each model response in a
+// turn is emitted as a single EventToken rather than many small ones.
+func (s *Session) RunTurn(ctx context.Context, events chan<- TurnEvent) {
+	defer close(events)
+
+	resp, err := s.Query(ctx)
+	if err != nil {
+		events <- TurnEvent{Type: EventDone, Err: err}
+		return
+	}
+
+	if resp.Content != "" {
+		events <- TurnEvent{Type: EventToken, Content: resp.Content}
+	}
+
+	if len(resp.ToolCalls) > 0 {
+		s.AddAssistantMessage(resp.Content, resp.ToolCalls...)
+
+		for _, toolCall := range resp.ToolCalls {
+			toolCall := toolCall
+			events <- TurnEvent{Type: EventToolCallStarted, ToolCall: &toolCall}
+
+			result, err := s.ExecuteTool(ctx, toolCall)
+			if err != nil {
+				events <- TurnEvent{Type: EventDone, Err: err}
+				return
+			}
+			events <- TurnEvent{Type: EventToolCallResult, ToolCall: &toolCall, Result: result}
+		}
+
+		resp, err = s.Query(ctx)
+		if err != nil {
+			events <- TurnEvent{Type: EventDone, Err: err}
+			return
+		}
+		if resp.Content != "" {
+			events <- TurnEvent{Type: EventToken, Content: resp.Content}
+		}
+	}
+
+	s.AddAssistantMessage(resp.Content)
+	events <- TurnEvent{Type: EventDone}
+}
@@ -0,0 +1,74 @@
+package core
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// turnMockModel is a minimal AIModel used to drive RunTurn in tests. It
+// returns toolResponse on the first Query call and finalResponse on every
+// call after that, mimicking a turn that calls a tool once.
+type turnMockModel struct {
+	calls         int
+	toolResponse  *Response
+	finalResponse *Response
+}
+
+func (m *turnMockModel) Query(ctx context.Context, messages []Message, tools []Tool, opts *QueryOptions) (*Response, error) {
+	m.calls++
+	if m.calls == 1 && m.toolResponse != nil {
+		return m.toolResponse, nil
+	}
+	return m.finalResponse, nil
+}
+
+func (m *turnMockModel) StreamQuery(ctx context.Context, messages []Message, tools []Tool, opts *QueryOptions) (<-chan ResponseChunk, error) {
+	ch := make(chan ResponseChunk)
+	close(ch)
+	return ch, nil
+}
+
+func (m *turnMockModel) Name() string     { return "turn-mock" }
+func (m *turnMockModel) Provider() string { return "mock" }
+
+func TestRunTurnWithToolCall(t *testing.T) {
+	model := &turnMockModel{
+		toolResponse: &Response{
+			ToolCalls: []ToolCall{{ID: "1", ToolName: "MockTool", Input: map[string]interface{}{}}},
+		},
+		finalResponse: &Response{Content: "final answer"},
+	}
+	tool := &MockTool{name: "MockTool", description: "mock", readOnly: true}
+
+	session := NewSession(model, []Tool{tool}, &SessionConfig{SystemPrompt: "test"})
+	session.AddUserMessage("do the thing")
+
+	events := make(chan TurnEvent)
+	go session.RunTurn(context.Background(), events)
+
+	var seen []EventType
+	for evt := range events {
+		seen = append(seen, evt.Type)
+	}
+
+	assert.Equal(t, []EventType{EventToolCallStarted, EventToolCallResult, EventToken, EventDone}, seen)
+	assert.Equal(t, "final answer", session.Messages[len(session.Messages)-1].Content)
+}
+
+func TestRunTurnWithoutToolCall(t *testing.T) {
+	model := &turnMockModel{finalResponse: &Response{Content: "hello"}}
+	session := NewSession(model, nil, &SessionConfig{SystemPrompt: "test"})
+	session.AddUserMessage("hi")
+
+	events := make(chan TurnEvent)
+	go session.RunTurn(context.Background(), events)
+
+	var seen []EventType
+	for evt := range events {
+		seen = append(seen, evt.Type)
+	}
+
+	assert.Equal(t, []EventType{EventToken, EventDone}, seen)
+}
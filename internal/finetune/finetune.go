@@ -0,0 +1,81 @@
+// Package finetune provides a provider-agnostic interface for creating and
+// monitoring fine-tuning jobs, in the way the AIModel interface in
+// internal/ai does for chat completions.
+package finetune
+
+import "context"
+
+// Hyperparameters controls how a fine-tuning run trains the base model.
+// Zero values leave the corresponding parameter at the provider's default.
+type Hyperparameters struct {
+	NEpochs                int     `json:"n_epochs,omitempty"`
+	BatchSize              int     `json:"batch_size,omitempty"`
+	LearningRateMultiplier float64 `json:"learning_rate_multiplier,omitempty"`
+}
+
+// JobRequest describes a fine-tuning job to create.
+type JobRequest struct {
+	// TrainingFile is the provider-side file id of the uploaded JSONL
+	// training data (see FineTuner.UploadFile).
+	TrainingFile string
+	// ValidationFile is an optional provider-side file id used to report
+	// validation metrics during training.
+	ValidationFile string
+	// Model is the base model to fine-tune (e.g. "gpt-3.5-turbo").
+	Model string
+	// Hyperparameters overrides the provider's default training parameters.
+	Hyperparameters Hyperparameters
+	// Suffix is appended to the resulting fine-tuned model's name.
+	Suffix string
+}
+
+// Job is the state of a fine-tuning job as last reported by the provider.
+type Job struct {
+	ID             string
+	Status         string
+	Model          string
+	FineTunedModel string
+	CreatedAt      int64
+	FinishedAt     int64
+	Error          string
+}
+
+// Event is a single log line emitted over the lifetime of a job, as
+// returned by FineTuner.StreamEvents.
+type Event struct {
+	ID        string
+	CreatedAt int64
+	Level     string
+	Message   string
+}
+
+// ListOpts constrains a FineTuner.List call.
+type ListOpts struct {
+	// Limit caps the number of jobs returned (0 means the provider's default).
+	Limit int
+	// After paginates starting after the given job id.
+	After string
+}
+
+// FineTuner manages the lifecycle of fine-tuning jobs against a provider.
+type FineTuner interface {
+	// UploadFile uploads a JSONL training or validation file and returns the
+	// provider-side file id to reference in a JobRequest.
+	UploadFile(ctx context.Context, name string, content []byte) (fileID string, err error)
+
+	// CreateJob starts a fine-tuning job and returns its initial state.
+	CreateJob(ctx context.Context, req JobRequest) (*Job, error)
+
+	// Retrieve fetches the current state of job id.
+	Retrieve(ctx context.Context, id string) (*Job, error)
+
+	// Cancel stops job id, if it hasn't already finished.
+	Cancel(ctx context.Context, id string) (*Job, error)
+
+	// List returns jobs matching opts, most recent first.
+	List(ctx context.Context, opts ListOpts) ([]Job, error)
+
+	// StreamEvents tails job id's event log, closing the channel once the
+	// job reaches a terminal state or ctx is canceled.
+	StreamEvents(ctx context.Context, id string) (<-chan Event, error)
+}
@@ -0,0 +1,317 @@
+package finetune
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	openAIDefaultBaseURL = "https://api.openai.com"
+
+	// openAIEventPollInterval is how often StreamEvents re-polls the events
+	// endpoint while the job is still running. OpenAI's fine-tuning events
+	// API is a plain paginated list, not SSE, so tailing it means polling.
+	openAIEventPollInterval = 5 * time.Second
+)
+
+// OpenAIFineTuner implements FineTuner against OpenAI's
+// /v1/fine_tuning/jobs API.
+type OpenAIFineTuner struct {
+	httpClient *http.Client
+	apiKey     string
+	baseURL    string
+}
+
+// NewOpenAIFineTuner creates a new OpenAIFineTuner.
+func NewOpenAIFineTuner(apiKey, baseURL string) (*OpenAIFineTuner, error) {
+	if apiKey == "" {
+		return nil, errors.New("API key is required")
+	}
+
+	if baseURL == "" {
+		baseURL = openAIDefaultBaseURL
+	}
+
+	return &OpenAIFineTuner{
+		httpClient: http.DefaultClient,
+		apiKey:     apiKey,
+		baseURL:    strings.TrimRight(baseURL, "/"),
+	}, nil
+}
+
+type openAIFile struct {
+	ID string `json:"id"`
+}
+
+type openAIHyperparameters struct {
+	NEpochs                interface{} `json:"n_epochs,omitempty"`
+	BatchSize              interface{} `json:"batch_size,omitempty"`
+	LearningRateMultiplier interface{} `json:"learning_rate_multiplier,omitempty"`
+}
+
+type openAIJobRequest struct {
+	TrainingFile    string                `json:"training_file"`
+	ValidationFile  string                `json:"validation_file,omitempty"`
+	Model           string                `json:"model"`
+	Hyperparameters openAIHyperparameters `json:"hyperparameters,omitempty"`
+	Suffix          string                `json:"suffix,omitempty"`
+}
+
+type openAIJob struct {
+	ID             string `json:"id"`
+	Status         string `json:"status"`
+	Model          string `json:"model"`
+	FineTunedModel string `json:"fine_tuned_model"`
+	CreatedAt      int64  `json:"created_at"`
+	FinishedAt     int64  `json:"finished_at"`
+	Error          *struct {
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+type openAIJobList struct {
+	Data []openAIJob `json:"data"`
+}
+
+type openAIEvent struct {
+	ID        string `json:"id"`
+	CreatedAt int64  `json:"created_at"`
+	Level     string `json:"level"`
+	Message   string `json:"message"`
+}
+
+type openAIEventList struct {
+	Data []openAIEvent `json:"data"`
+}
+
+type openAIAPIError struct {
+	Error struct {
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+func toJob(j openAIJob) *Job {
+	job := &Job{
+		ID:             j.ID,
+		Status:         j.Status,
+		Model:          j.Model,
+		FineTunedModel: j.FineTunedModel,
+		CreatedAt:      j.CreatedAt,
+		FinishedAt:     j.FinishedAt,
+	}
+	if j.Error != nil {
+		job.Error = j.Error.Message
+	}
+	return job
+}
+
+// UploadFile uploads a JSONL training/validation file via OpenAI's
+// multipart /v1/files endpoint and returns the resulting file id.
+func (f *OpenAIFineTuner) UploadFile(ctx context.Context, name string, content []byte) (string, error) {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+
+	if err := writer.WriteField("purpose", "fine-tune"); err != nil {
+		return "", err
+	}
+
+	part, err := writer.CreateFormFile("file", name)
+	if err != nil {
+		return "", err
+	}
+	if _, err := part.Write(content); err != nil {
+		return "", err
+	}
+	if err := writer.Close(); err != nil {
+		return "", err
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, f.baseURL+"/v1/files", &body)
+	if err != nil {
+		return "", err
+	}
+	httpReq.Header.Set("Content-Type", writer.FormDataContentType())
+	f.setHeaders(httpReq)
+
+	var file openAIFile
+	if err := f.doRequest(httpReq, &file); err != nil {
+		return "", err
+	}
+	return file.ID, nil
+}
+
+// CreateJob starts a fine-tuning job.
+func (f *OpenAIFineTuner) CreateJob(ctx context.Context, req JobRequest) (*Job, error) {
+	reqBody := openAIJobRequest{
+		TrainingFile:   req.TrainingFile,
+		ValidationFile: req.ValidationFile,
+		Model:          req.Model,
+		Suffix:         req.Suffix,
+	}
+	if req.Hyperparameters.NEpochs != 0 {
+		reqBody.Hyperparameters.NEpochs = req.Hyperparameters.NEpochs
+	}
+	if req.Hyperparameters.BatchSize != 0 {
+		reqBody.Hyperparameters.BatchSize = req.Hyperparameters.BatchSize
+	}
+	if req.Hyperparameters.LearningRateMultiplier != 0 {
+		reqBody.Hyperparameters.LearningRateMultiplier = req.Hyperparameters.LearningRateMultiplier
+	}
+
+	var job openAIJob
+	if err := f.doJSONRequest(ctx, http.MethodPost, "/v1/fine_tuning/jobs", reqBody, &job); err != nil {
+		return nil, err
+	}
+	return toJob(job), nil
+}
+
+// Retrieve fetches the current state of job id.
+func (f *OpenAIFineTuner) Retrieve(ctx context.Context, id string) (*Job, error) {
+	var job openAIJob
+	if err := f.doJSONRequest(ctx, http.MethodGet, "/v1/fine_tuning/jobs/"+id, nil, &job); err != nil {
+		return nil, err
+	}
+	return toJob(job), nil
+}
+
+// Cancel stops job id, if it hasn't already finished.
+func (f *OpenAIFineTuner) Cancel(ctx context.Context, id string) (*Job, error) {
+	var job openAIJob
+	if err := f.doJSONRequest(ctx, http.MethodPost, "/v1/fine_tuning/jobs/"+id+"/cancel", nil, &job); err != nil {
+		return nil, err
+	}
+	return toJob(job), nil
+}
+
+// List returns jobs matching opts, most recent first.
+func (f *OpenAIFineTuner) List(ctx context.Context, opts ListOpts) ([]Job, error) {
+	path := "/v1/fine_tuning/jobs"
+	query := make([]string, 0, 2)
+	if opts.Limit > 0 {
+		query = append(query, fmt.Sprintf("limit=%d", opts.Limit))
+	}
+	if opts.After != "" {
+		query = append(query, "after="+opts.After)
+	}
+	if len(query) > 0 {
+		path += "?" + strings.Join(query, "&")
+	}
+
+	var list openAIJobList
+	if err := f.doJSONRequest(ctx, http.MethodGet, path, nil, &list); err != nil {
+		return nil, err
+	}
+
+	jobs := make([]Job, len(list.Data))
+	for i, j := range list.Data {
+		jobs[i] = *toJob(j)
+	}
+	return jobs, nil
+}
+
+// isTerminal reports whether a fine-tuning job status will never change again.
+func isTerminal(status string) bool {
+	switch status {
+	case "succeeded", "failed", "cancelled":
+		return true
+	default:
+		return false
+	}
+}
+
+// StreamEvents polls job id's event log every openAIEventPollInterval,
+// forwarding events not yet seen, until the job reaches a terminal status
+// or ctx is canceled.
+func (f *OpenAIFineTuner) StreamEvents(ctx context.Context, id string) (<-chan Event, error) {
+	eventCh := make(chan Event)
+
+	go func() {
+		defer close(eventCh)
+
+		seen := make(map[string]bool)
+		ticker := time.NewTicker(openAIEventPollInterval)
+		defer ticker.Stop()
+
+		for {
+			var list openAIEventList
+			if err := f.doJSONRequest(ctx, http.MethodGet, "/v1/fine_tuning/jobs/"+id+"/events", nil, &list); err == nil {
+				// The API returns newest-first; emit in chronological order.
+				for i := len(list.Data) - 1; i >= 0; i-- {
+					e := list.Data[i]
+					if seen[e.ID] {
+						continue
+					}
+					seen[e.ID] = true
+					eventCh <- Event{ID: e.ID, CreatedAt: e.CreatedAt, Level: e.Level, Message: e.Message}
+				}
+			}
+
+			job, err := f.Retrieve(ctx, id)
+			if err == nil && isTerminal(job.Status) {
+				return
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+
+	return eventCh, nil
+}
+
+func (f *OpenAIFineTuner) doJSONRequest(ctx context.Context, method, path string, reqBody interface{}, out interface{}) error {
+	var bodyReader *bytes.Reader
+	if reqBody != nil {
+		data, err := json.Marshal(reqBody)
+		if err != nil {
+			return err
+		}
+		bodyReader = bytes.NewReader(data)
+	} else {
+		bodyReader = bytes.NewReader(nil)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, method, f.baseURL+path, bodyReader)
+	if err != nil {
+		return err
+	}
+	if reqBody != nil {
+		httpReq.Header.Set("Content-Type", "application/json")
+	}
+	f.setHeaders(httpReq)
+
+	return f.doRequest(httpReq, out)
+}
+
+func (f *OpenAIFineTuner) doRequest(httpReq *http.Request, out interface{}) error {
+	httpResp, err := f.httpClient.Do(httpReq)
+	if err != nil {
+		return fmt.Errorf("OpenAI API error: %w", err)
+	}
+	defer httpResp.Body.Close()
+
+	if httpResp.StatusCode >= 300 {
+		var apiErr openAIAPIError
+		_ = json.NewDecoder(httpResp.Body).Decode(&apiErr)
+		if apiErr.Error.Message != "" {
+			return fmt.Errorf("OpenAI API error: %s", apiErr.Error.Message)
+		}
+		return fmt.Errorf("OpenAI API error: unexpected status %d", httpResp.StatusCode)
+	}
+
+	return json.NewDecoder(httpResp.Body).Decode(out)
+}
+
+func (f *OpenAIFineTuner) setHeaders(req *http.Request) {
+	req.Header.Set("Authorization", "Bearer "+f.apiKey)
+}
@@ -0,0 +1,50 @@
+package finetune
+
+import "testing"
+
+func TestToJob(t *testing.T) {
+	j := openAIJob{
+		ID:             "ftjob-1",
+		Status:         "succeeded",
+		Model:          "gpt-3.5-turbo",
+		FineTunedModel: "ft:gpt-3.5-turbo:acme::abc123",
+		CreatedAt:      1000,
+		FinishedAt:     2000,
+	}
+
+	job := toJob(j)
+	if job.ID != "ftjob-1" || job.Status != "succeeded" || job.FineTunedModel != "ft:gpt-3.5-turbo:acme::abc123" {
+		t.Fatalf("unexpected job: %+v", job)
+	}
+	if job.Error != "" {
+		t.Fatalf("expected no error, got %q", job.Error)
+	}
+}
+
+func TestToJobWithError(t *testing.T) {
+	j := openAIJob{ID: "ftjob-2", Status: "failed"}
+	j.Error = &struct {
+		Message string `json:"message"`
+	}{Message: "training data invalid"}
+
+	job := toJob(j)
+	if job.Error != "training data invalid" {
+		t.Fatalf("expected error message to be mapped, got %q", job.Error)
+	}
+}
+
+func TestIsTerminal(t *testing.T) {
+	terminal := []string{"succeeded", "failed", "cancelled"}
+	for _, status := range terminal {
+		if !isTerminal(status) {
+			t.Errorf("expected %q to be terminal", status)
+		}
+	}
+
+	nonTerminal := []string{"validating_files", "queued", "running"}
+	for _, status := range nonTerminal {
+		if isTerminal(status) {
+			t.Errorf("expected %q to not be terminal", status)
+		}
+	}
+}
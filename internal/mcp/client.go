@@ -0,0 +1,125 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/cinience/aigo-kode/internal/config"
+)
+
+// reconnectBackoff is the delay schedule Client steps through each time a
+// call fails, reconnecting before each retry, before giving up.
+var reconnectBackoff = []time.Duration{time.Second, 2 * time.Second, 5 * time.Second}
+
+// Client is a connection to one MCP server, reconnecting with backoff if
+// the underlying transport (a stdio subprocess or an HTTP endpoint) drops.
+type Client struct {
+	name string
+	cfg  config.MCPServerConfig
+
+	transport transport
+}
+
+// NewClient connects to the MCP server described by cfg (named name, used
+// for logging and to namespace its tools) and performs the initialize
+// handshake.
+func NewClient(name string, cfg config.MCPServerConfig) (*Client, error) {
+	if cfg.Command == "" && cfg.URL == "" {
+		return nil, fmt.Errorf("mcp: server %q has neither command nor url configured", name)
+	}
+
+	c := &Client{name: name, cfg: cfg}
+	if err := c.reconnect(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func (c *Client) dial() (transport, error) {
+	if c.cfg.Command != "" {
+		return newStdioTransport(c.cfg.Command, c.cfg.Args, c.cfg.Env)
+	}
+	return newHTTPTransport(c.cfg.URL, c.cfg.Headers), nil
+}
+
+func (c *Client) reconnect() error {
+	t, err := c.dial()
+	if err != nil {
+		return fmt.Errorf("mcp: connecting to %q: %w", c.name, err)
+	}
+
+	var result initializeResult
+	err = t.call("initialize", initializeParams{
+		ProtocolVersion: protocolVersion,
+		Capabilities:    map[string]interface{}{},
+		ClientInfo:      clientInfo{Name: "aigo-kode", Version: "1.0"},
+	}, &result)
+	if err != nil {
+		t.close()
+		return fmt.Errorf("mcp: initializing %q: %w", c.name, err)
+	}
+	if err := t.notify("notifications/initialized", map[string]interface{}{}); err != nil {
+		t.close()
+		return fmt.Errorf("mcp: initializing %q: %w", c.name, err)
+	}
+
+	if c.transport != nil {
+		c.transport.close()
+	}
+	c.transport = t
+	return nil
+}
+
+// call invokes method on the server, reconnecting with exponential-ish
+// backoff if the transport has gone away (e.g. a stdio subprocess that
+// crashed), before giving up.
+func (c *Client) call(ctx context.Context, method string, params, result interface{}) error {
+	err := c.transport.call(method, params, result)
+	if err == nil {
+		return nil
+	}
+
+	for _, delay := range reconnectBackoff {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(delay):
+		}
+
+		if rerr := c.reconnect(); rerr != nil {
+			err = rerr
+			continue
+		}
+		return c.transport.call(method, params, result)
+	}
+	return fmt.Errorf("mcp: %q unreachable after %d retries: %w", c.name, len(reconnectBackoff), err)
+}
+
+// ListTools returns every tool the server advertises.
+func (c *Client) ListTools(ctx context.Context) ([]ToolDef, error) {
+	var result toolsListResult
+	if err := c.call(ctx, "tools/list", map[string]interface{}{}, &result); err != nil {
+		return nil, err
+	}
+	return result.Tools, nil
+}
+
+// CallTool invokes the server tool named name with arguments, returning
+// its text content concatenated and whether the server flagged the result
+// as an error.
+func (c *Client) CallTool(ctx context.Context, name string, arguments map[string]interface{}) (string, bool, error) {
+	var result toolsCallResult
+	if err := c.call(ctx, "tools/call", toolsCallParams{Name: name, Arguments: arguments}, &result); err != nil {
+		return "", false, err
+	}
+
+	var text strings.Builder
+	for _, block := range result.Content {
+		if block.Type == "text" {
+			text.WriteString(block.Text)
+		}
+	}
+	return text.String(), result.IsError, nil
+}
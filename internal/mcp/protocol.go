@@ -0,0 +1,91 @@
+package mcp
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// protocolVersion is the MCP protocol version this client speaks.
+const protocolVersion = "2024-11-05"
+
+// rpcError is a JSON-RPC 2.0 error object.
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *rpcError) Error() string {
+	return fmt.Sprintf("mcp: server returned error %d: %s", e.Code, e.Message)
+}
+
+// rpcRequest is a JSON-RPC 2.0 request, sent with an ID so the matching
+// rpcResponse can be paired back to it.
+type rpcRequest struct {
+	JSONRPC string      `json:"jsonrpc"`
+	ID      int64       `json:"id"`
+	Method  string      `json:"method"`
+	Params  interface{} `json:"params,omitempty"`
+}
+
+// rpcNotification is a one-way JSON-RPC 2.0 message with no ID and no
+// response expected, e.g. "notifications/initialized".
+type rpcNotification struct {
+	JSONRPC string      `json:"jsonrpc"`
+	Method  string      `json:"method"`
+	Params  interface{} `json:"params,omitempty"`
+}
+
+// rpcResponse is a JSON-RPC 2.0 response. Result is left raw so each
+// caller can unmarshal it into the MCP result type it expects.
+type rpcResponse struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      int64           `json:"id"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+}
+
+// clientInfo/serverInfo identify the two ends of the handshake.
+type clientInfo struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+type initializeParams struct {
+	ProtocolVersion string                 `json:"protocolVersion"`
+	Capabilities    map[string]interface{} `json:"capabilities"`
+	ClientInfo      clientInfo             `json:"clientInfo"`
+}
+
+type initializeResult struct {
+	ProtocolVersion string     `json:"protocolVersion"`
+	ServerInfo      clientInfo `json:"serverInfo"`
+}
+
+// ToolDef is one entry of a tools/list result.
+type ToolDef struct {
+	Name        string          `json:"name"`
+	Description string          `json:"description"`
+	InputSchema json.RawMessage `json:"inputSchema"`
+}
+
+type toolsListResult struct {
+	Tools []ToolDef `json:"tools"`
+}
+
+type toolsCallParams struct {
+	Name      string                 `json:"name"`
+	Arguments map[string]interface{} `json:"arguments,omitempty"`
+}
+
+// contentBlock is one block of a tools/call result. Only "text" blocks are
+// surfaced to the model today; image/resource blocks are dropped rather
+// than guessed at.
+type contentBlock struct {
+	Type string `json:"type"`
+	Text string `json:"text,omitempty"`
+}
+
+type toolsCallResult struct {
+	Content []contentBlock `json:"content"`
+	IsError bool           `json:"isError,omitempty"`
+}
@@ -0,0 +1,103 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// Tool adapts one MCP server-advertised tool to the core.Tool interface,
+// proxying Execute to the server's tools/call. Its exposed name is
+// namespaced by server (mcp_<server>_<tool>) so a third-party server can't
+// collide with a built-in tool like Bash or with another server's tool of
+// the same name.
+type Tool struct {
+	client     *Client
+	remoteName string // the tool's name as the server knows it
+	name       string // namespaced name exposed to the session
+	desc       string
+	properties string // JSON Schema "properties" fragment
+}
+
+// NewTool wraps client's tool def as a core.Tool, namespaced under
+// serverName.
+func NewTool(client *Client, serverName string, def ToolDef) *Tool {
+	return &Tool{
+		client:     client,
+		remoteName: def.Name,
+		name:       fmt.Sprintf("mcp_%s_%s", serverName, def.Name),
+		desc:       def.Description,
+		properties: propertiesFragment(def.InputSchema),
+	}
+}
+
+// propertiesFragment extracts the "properties" field from a full JSON
+// Schema document, the shape core.Tool.Arguments() is expected to return
+// (callers wrap it back into a full "type":"object" document, the same way
+// every built-in tool's generated schema is wrapped before it reaches a
+// model).
+func propertiesFragment(inputSchema json.RawMessage) string {
+	var doc struct {
+		Properties json.RawMessage `json:"properties"`
+	}
+	if err := json.Unmarshal(inputSchema, &doc); err != nil || len(doc.Properties) == 0 {
+		return "{}"
+	}
+	return string(doc.Properties)
+}
+
+// Name returns the tool's namespaced name
+func (t *Tool) Name() string {
+	return t.name
+}
+
+// Description returns the tool description the server advertised
+func (t *Tool) Description() string {
+	return t.desc
+}
+
+// Arguments returns the JSON Schema properties fragment the server
+// advertised for this tool's input
+func (t *Tool) Arguments() string {
+	return t.properties
+}
+
+// OutputSchema returns "" - an MCP tool's result is free-form content
+// blocks, with no fixed shape to declare up front.
+func (t *Tool) OutputSchema() string {
+	return ""
+}
+
+// Execute proxies to the server's tools/call, returning the concatenated
+// text content as the tool's output.
+func (t *Tool) Execute(ctx context.Context, input map[string]interface{}) (interface{}, error) {
+	text, isError, err := t.client.CallTool(ctx, t.remoteName, input)
+	if err != nil {
+		return nil, err
+	}
+	if isError {
+		return nil, errors.New(text)
+	}
+	return text, nil
+}
+
+// ValidateInput is a no-op - ExecuteTool already validates input against
+// Arguments()'s generic JSON Schema before ValidateInput runs, and an MCP
+// tool has no further domain-specific checks of its own to apply.
+func (t *Tool) ValidateInput(input map[string]interface{}) error {
+	return nil
+}
+
+// IsReadOnly returns false - MCP tools run arbitrary server-defined code,
+// so none are assumed safe to run without confirmation.
+func (t *Tool) IsReadOnly() bool {
+	return false
+}
+
+// RequiresPermission always returns true - every MCP tool is untrusted by
+// default, since its actual behavior is defined by a third-party server
+// this binary didn't ship.
+func (t *Tool) RequiresPermission(input map[string]interface{}) bool {
+	return true
+}
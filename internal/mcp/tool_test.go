@@ -0,0 +1,31 @@
+package mcp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPropertiesFragment(t *testing.T) {
+	got := propertiesFragment([]byte(`{"type":"object","properties":{"path":{"type":"string"}}}`))
+	assert.Equal(t, `{"path":{"type":"string"}}`, got)
+}
+
+func TestPropertiesFragmentMissingProperties(t *testing.T) {
+	assert.Equal(t, "{}", propertiesFragment([]byte(`{"type":"object"}`)))
+	assert.Equal(t, "{}", propertiesFragment([]byte(`not json`)))
+}
+
+func TestNewToolNamespacesName(t *testing.T) {
+	tool := NewTool(nil, "github", ToolDef{
+		Name:        "create_issue",
+		Description: "Creates an issue",
+		InputSchema: []byte(`{"type":"object","properties":{"title":{"type":"string"}}}`),
+	})
+
+	assert.Equal(t, "mcp_github_create_issue", tool.Name())
+	assert.Equal(t, "Creates an issue", tool.Description())
+	assert.Equal(t, `{"title":{"type":"string"}}`, tool.Arguments())
+	assert.True(t, tool.RequiresPermission(nil))
+	assert.False(t, tool.IsReadOnly())
+}
@@ -0,0 +1,196 @@
+package mcp
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"sync"
+	"sync/atomic"
+)
+
+// transport speaks JSON-RPC 2.0 to a single MCP server, over whichever
+// channel the server is configured for (a stdio subprocess or an HTTP
+// endpoint).
+type transport interface {
+	call(method string, params interface{}, result interface{}) error
+	notify(method string, params interface{}) error
+	close() error
+}
+
+// stdioTransport speaks newline-delimited JSON-RPC over a subprocess's
+// stdin/stdout, the transport MCP servers launched locally use.
+type stdioTransport struct {
+	cmd *exec.Cmd
+	w   io.WriteCloser
+	wMu sync.Mutex
+
+	nextID  int64
+	pending sync.Map // int64 -> chan rpcResponse
+}
+
+func newStdioTransport(command string, args []string, env map[string]string) (*stdioTransport, error) {
+	cmd := exec.Command(command, args...)
+	if len(env) > 0 {
+		cmd.Env = os.Environ()
+		for k, v := range env {
+			cmd.Env = append(cmd.Env, k+"="+v)
+		}
+	}
+	cmd.Stderr = os.Stderr
+
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+
+	t := &stdioTransport{cmd: cmd, w: stdin}
+	go t.readLoop(bufio.NewReader(stdout))
+	return t, nil
+}
+
+func (t *stdioTransport) call(method string, params, result interface{}) error {
+	id := atomic.AddInt64(&t.nextID, 1)
+	ch := make(chan rpcResponse, 1)
+	t.pending.Store(id, ch)
+	defer t.pending.Delete(id)
+
+	if err := t.write(rpcRequest{JSONRPC: "2.0", ID: id, Method: method, Params: params}); err != nil {
+		return err
+	}
+
+	resp := <-ch
+	if resp.Error != nil {
+		return resp.Error
+	}
+	if result == nil || len(resp.Result) == 0 {
+		return nil
+	}
+	return json.Unmarshal(resp.Result, result)
+}
+
+func (t *stdioTransport) notify(method string, params interface{}) error {
+	return t.write(rpcNotification{JSONRPC: "2.0", Method: method, Params: params})
+}
+
+func (t *stdioTransport) write(v interface{}) error {
+	body, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	t.wMu.Lock()
+	defer t.wMu.Unlock()
+	_, err = t.w.Write(append(body, '\n'))
+	return err
+}
+
+// readLoop reads one JSON-RPC message per line and dispatches responses to
+// the pending call awaiting that ID. Unsolicited server notifications have
+// no ID to match against and are silently dropped, since this client has
+// nothing registered to act on them.
+func (t *stdioTransport) readLoop(r *bufio.Reader) {
+	for {
+		line, err := r.ReadBytes('\n')
+		if len(bytes.TrimSpace(line)) > 0 {
+			var resp rpcResponse
+			if jsonErr := json.Unmarshal(line, &resp); jsonErr == nil && resp.ID != 0 {
+				if ch, ok := t.pending.Load(resp.ID); ok {
+					ch.(chan rpcResponse) <- resp
+				}
+			}
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+func (t *stdioTransport) close() error {
+	t.w.Close()
+	return t.cmd.Wait()
+}
+
+// httpTransport speaks JSON-RPC 2.0 over a plain HTTP POST per call, the
+// transport used by MCP servers reachable as a network endpoint rather
+// than launched as a subprocess.
+type httpTransport struct {
+	httpClient *http.Client
+	url        string
+	headers    map[string]string
+	nextID     int64
+}
+
+func newHTTPTransport(url string, headers map[string]string) *httpTransport {
+	return &httpTransport{httpClient: http.DefaultClient, url: url, headers: headers}
+}
+
+func (t *httpTransport) call(method string, params, result interface{}) error {
+	id := atomic.AddInt64(&t.nextID, 1)
+
+	env, err := t.post(rpcRequest{JSONRPC: "2.0", ID: id, Method: method, Params: params})
+	if err != nil {
+		return err
+	}
+	if env.Error != nil {
+		return env.Error
+	}
+	if result == nil || len(env.Result) == 0 {
+		return nil
+	}
+	return json.Unmarshal(env.Result, result)
+}
+
+func (t *httpTransport) notify(method string, params interface{}) error {
+	_, err := t.post(rpcNotification{JSONRPC: "2.0", Method: method, Params: params})
+	return err
+}
+
+func (t *httpTransport) post(v interface{}) (rpcResponse, error) {
+	body, err := json.Marshal(v)
+	if err != nil {
+		return rpcResponse{}, err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, t.url, bytes.NewReader(body))
+	if err != nil {
+		return rpcResponse{}, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range t.headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := t.httpClient.Do(req)
+	if err != nil {
+		return rpcResponse{}, fmt.Errorf("mcp: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(resp.Body)
+		return rpcResponse{}, fmt.Errorf("mcp: unexpected status %d: %s", resp.StatusCode, respBody)
+	}
+
+	var env rpcResponse
+	if resp.ContentLength == 0 {
+		return env, nil
+	}
+	err = json.NewDecoder(resp.Body).Decode(&env)
+	return env, err
+}
+
+func (t *httpTransport) close() error {
+	return nil
+}
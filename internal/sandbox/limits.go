@@ -0,0 +1,18 @@
+package sandbox
+
+// ResourceLimits are OS-level resource caps applied to a sandboxed command.
+// A zero value leaves every limit at the OS default (effectively
+// unlimited).
+type ResourceLimits struct {
+	// CPUSeconds caps CPU time (RLIMIT_CPU).
+	CPUSeconds uint64
+	// MaxMemoryBytes caps the address space size (RLIMIT_AS).
+	MaxMemoryBytes uint64
+	// MaxOpenFiles caps the number of open file descriptors (RLIMIT_NOFILE).
+	MaxOpenFiles uint64
+}
+
+// IsZero reports whether every limit is unset.
+func (l ResourceLimits) IsZero() bool {
+	return l.CPUSeconds == 0 && l.MaxMemoryBytes == 0 && l.MaxOpenFiles == 0
+}
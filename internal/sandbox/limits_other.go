@@ -0,0 +1,8 @@
+//go:build !unix
+
+package sandbox
+
+// apply is a no-op on platforms without POSIX rlimits.
+func (l ResourceLimits) apply() (restore func(), err error) {
+	return func() {}, nil
+}
@@ -0,0 +1,48 @@
+//go:build unix
+
+package sandbox
+
+import "syscall"
+
+// apply sets the process's rlimits to l's values via syscall.Setrlimit.
+// Limits set this way are inherited by any child process forked
+// afterwards, so calling apply immediately before starting a command and
+// restore immediately after Start returns confines the child without
+// permanently changing the caller's own limits.
+func (l ResourceLimits) apply() (restore func(), err error) {
+	if l.IsZero() {
+		return func() {}, nil
+	}
+
+	saved := map[int]syscall.Rlimit{}
+	want := map[int]uint64{
+		syscall.RLIMIT_CPU:    l.CPUSeconds,
+		syscall.RLIMIT_AS:     l.MaxMemoryBytes,
+		syscall.RLIMIT_NOFILE: l.MaxOpenFiles,
+	}
+
+	for resource, value := range want {
+		if value == 0 {
+			continue
+		}
+		var current syscall.Rlimit
+		if err := syscall.Getrlimit(resource, &current); err != nil {
+			return nil, err
+		}
+		saved[resource] = current
+
+		if err := syscall.Setrlimit(resource, &syscall.Rlimit{Cur: value, Max: current.Max}); err != nil {
+			restoreSaved(saved)
+			return nil, err
+		}
+	}
+
+	return func() { restoreSaved(saved) }, nil
+}
+
+func restoreSaved(saved map[int]syscall.Rlimit) {
+	for resource, limit := range saved {
+		limit := limit
+		_ = syscall.Setrlimit(resource, &limit)
+	}
+}
@@ -0,0 +1,25 @@
+//go:build linux
+
+package sandbox
+
+// wrapForIsolation rewrites name/args to run under `unshare`, isolating the
+// command's network and/or PID namespace from the host when requested.
+// Both namespaces are supported only on Linux; on any other OS the caller's
+// command runs unmodified regardless of the isolation flags.
+func wrapForIsolation(netIsolation, pidIsolation bool, name string, args []string) (string, []string) {
+	if !netIsolation && !pidIsolation {
+		return name, args
+	}
+
+	unshareArgs := []string{}
+	if netIsolation {
+		unshareArgs = append(unshareArgs, "--net")
+	}
+	if pidIsolation {
+		unshareArgs = append(unshareArgs, "--pid", "--mount-proc", "--fork")
+	}
+	unshareArgs = append(unshareArgs, "--", name)
+	unshareArgs = append(unshareArgs, args...)
+
+	return "unshare", unshareArgs
+}
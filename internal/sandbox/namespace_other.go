@@ -0,0 +1,9 @@
+//go:build !linux
+
+package sandbox
+
+// wrapForIsolation is a no-op on non-Linux platforms: network/PID namespace
+// isolation via unshare is Linux-specific, so the command runs unmodified.
+func wrapForIsolation(netIsolation, pidIsolation bool, name string, args []string) (string, []string) {
+	return name, args
+}
@@ -0,0 +1,120 @@
+package sandbox
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// PersistentSession keeps a single long-lived `bash -i` process running
+// behind a Sandbox, so that cwd and environment changes from one Run
+// persist into the next - similar to how a REPL tool would work, instead
+// of BashTool's default of starting a fresh process per call.
+type PersistentSession struct {
+	sandbox *Sandbox
+
+	mu     sync.Mutex
+	cmd    *exec.Cmd
+	stdin  io.WriteCloser
+	stdout *bufio.Reader
+	lines  chan string
+}
+
+// NewPersistentSession starts the underlying `bash -i` process, applying
+// sandbox's resource limits and namespace isolation the same way Start
+// would for a one-off command.
+func NewPersistentSession(ctx context.Context, sandbox *Sandbox) (*PersistentSession, error) {
+	name, args := wrapForIsolation(sandbox.NetworkIsolation, sandbox.PIDIsolation, "bash", []string{"-i"})
+	cmd := exec.CommandContext(ctx, name, args...)
+
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	stdoutPipe, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	cmd.Stderr = cmd.Stdout
+
+	restore, err := sandbox.Limits.apply()
+	if err != nil {
+		return nil, fmt.Errorf("sandbox: applying resource limits: %w", err)
+	}
+	startErr := cmd.Start()
+	restore()
+	if startErr != nil {
+		return nil, startErr
+	}
+
+	return &PersistentSession{
+		sandbox: sandbox,
+		cmd:     cmd,
+		stdin:   stdin,
+		stdout:  bufio.NewReader(stdoutPipe),
+	}, nil
+}
+
+// sentinelPrefix marks the end of a command's output in the session's
+// shared stdout stream, followed by its exit code, so Run can tell where
+// one command's output ends without waiting for the process to exit.
+const sentinelPrefix = "__sandbox_sentinel_"
+
+// Run submits command to the persistent bash process and waits for it to
+// finish, returning its combined stdout/stderr and exit code. cwd and
+// environment changes made by command are visible to the next Run.
+func (p *PersistentSession) Run(command string) (*Result, error) {
+	if err := p.sandbox.CheckPolicy(command, ""); err != nil {
+		return nil, err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	sentinel := sentinelPrefix + strconv.FormatInt(int64(len(command)), 10) + "_" + strconv.Itoa(sentinelCounter())
+	if _, err := fmt.Fprintf(p.stdin, "%s\necho \"%s:$?\"\n", command, sentinel); err != nil {
+		return nil, err
+	}
+
+	var output strings.Builder
+	for {
+		line, err := p.stdout.ReadString('\n')
+		if line != "" {
+			if strings.HasPrefix(strings.TrimRight(line, "\n"), sentinel+":") {
+				exitCode, _ := strconv.Atoi(strings.TrimSpace(strings.TrimPrefix(strings.TrimRight(line, "\n"), sentinel+":")))
+				return &Result{Stdout: output.String(), ExitCode: exitCode}, nil
+			}
+			output.WriteString(line)
+		}
+		if err != nil {
+			return &Result{Stdout: output.String()}, err
+		}
+	}
+}
+
+// Close terminates the persistent bash process.
+func (p *PersistentSession) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	_ = p.stdin.Close()
+	return p.cmd.Process.Kill()
+}
+
+var (
+	sentinelCounterMu sync.Mutex
+	sentinelCounterN  int
+)
+
+// sentinelCounter returns a process-unique, monotonically increasing
+// number so back-to-back Run calls never share a sentinel.
+func sentinelCounter() int {
+	sentinelCounterMu.Lock()
+	defer sentinelCounterMu.Unlock()
+	sentinelCounterN++
+	return sentinelCounterN
+}
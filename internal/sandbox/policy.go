@@ -0,0 +1,145 @@
+// Package sandbox runs shell commands under a configurable allow/deny
+// policy and OS resource limits, and streams their output incrementally so
+// callers can render progress and interrupt a run in flight.
+package sandbox
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// ArgPattern denies a binary's invocation when one of its arguments matches
+// Pattern (a regular expression).
+type ArgPattern struct {
+	Binary  string `json:"binary"`
+	Pattern string `json:"pattern"`
+}
+
+// Policy decides whether a shell command is allowed to run: which binaries
+// are allowed or denied, argument patterns those binaries must not match,
+// and which working directories a command may run from. A zero-value
+// Policy allows everything - callers opt into restrictions by populating
+// the fields that matter to them.
+type Policy struct {
+	// AllowedBinaries, if non-empty, is the complete set of binaries a
+	// command's first word may name. Anything else is denied.
+	AllowedBinaries []string `json:"allowedBinaries,omitempty"`
+	// DeniedBinaries is always checked, even when AllowedBinaries is set.
+	DeniedBinaries []string `json:"deniedBinaries,omitempty"`
+	// DeniedArgPatterns denies a command when its binary matches Binary
+	// and any argument matches Pattern.
+	DeniedArgPatterns []ArgPattern `json:"deniedArgPatterns,omitempty"`
+	// AllowedWorkingDirs, if non-empty, restricts execution to one of
+	// these directories (or a subdirectory of one of them).
+	AllowedWorkingDirs []string `json:"allowedWorkingDirs,omitempty"`
+
+	compiled []compiledArgPattern
+}
+
+type compiledArgPattern struct {
+	binary  string
+	pattern *regexp.Regexp
+}
+
+// compile lazily compiles DeniedArgPatterns' regular expressions, caching
+// the result on the Policy.
+func (p *Policy) compile() error {
+	if p.compiled != nil || len(p.DeniedArgPatterns) == 0 {
+		return nil
+	}
+	compiled := make([]compiledArgPattern, 0, len(p.DeniedArgPatterns))
+	for _, ap := range p.DeniedArgPatterns {
+		re, err := regexp.Compile(ap.Pattern)
+		if err != nil {
+			return fmt.Errorf("sandbox: invalid arg pattern %q for %q: %w", ap.Pattern, ap.Binary, err)
+		}
+		compiled = append(compiled, compiledArgPattern{binary: ap.Binary, pattern: re})
+	}
+	p.compiled = compiled
+	return nil
+}
+
+// Check returns an error if running binary with args in workingDir
+// violates the policy.
+func (p *Policy) Check(binary string, args []string, workingDir string) error {
+	if p == nil {
+		return nil
+	}
+	if err := p.compile(); err != nil {
+		return err
+	}
+
+	for _, denied := range p.DeniedBinaries {
+		if denied == binary {
+			return fmt.Errorf("sandbox: binary %q is denied by policy", binary)
+		}
+	}
+
+	if len(p.AllowedBinaries) > 0 {
+		allowed := false
+		for _, b := range p.AllowedBinaries {
+			if b == binary {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			return fmt.Errorf("sandbox: binary %q is not in the allowed list", binary)
+		}
+	}
+
+	for _, ap := range p.compiled {
+		if ap.binary != "" && ap.binary != binary {
+			continue
+		}
+		for _, arg := range args {
+			if ap.pattern.MatchString(arg) {
+				return fmt.Errorf("sandbox: argument %q to %q matches denied pattern %q", arg, binary, ap.pattern.String())
+			}
+		}
+	}
+
+	if len(p.AllowedWorkingDirs) > 0 && workingDir != "" {
+		allowed := false
+		for _, dir := range p.AllowedWorkingDirs {
+			if workingDir == dir || strings.HasPrefix(workingDir, strings.TrimSuffix(dir, "/")+"/") {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			return fmt.Errorf("sandbox: working directory %q is not allowed", workingDir)
+		}
+	}
+
+	return nil
+}
+
+// Merge returns a new Policy combining p with override: override's
+// non-empty AllowedBinaries and AllowedWorkingDirs replace p's, and its
+// DeniedBinaries and DeniedArgPatterns are appended to p's, so a denial in
+// either policy survives the merge.
+func (p *Policy) Merge(override *Policy) *Policy {
+	merged := &Policy{}
+	if p != nil {
+		*merged = *p
+		merged.compiled = nil
+	}
+	if override == nil {
+		return merged
+	}
+	if len(override.AllowedBinaries) > 0 {
+		merged.AllowedBinaries = override.AllowedBinaries
+	}
+	if len(override.DeniedBinaries) > 0 {
+		merged.DeniedBinaries = append(merged.DeniedBinaries, override.DeniedBinaries...)
+	}
+	if len(override.DeniedArgPatterns) > 0 {
+		merged.DeniedArgPatterns = append(merged.DeniedArgPatterns, override.DeniedArgPatterns...)
+	}
+	if len(override.AllowedWorkingDirs) > 0 {
+		merged.AllowedWorkingDirs = override.AllowedWorkingDirs
+	}
+	return merged
+}
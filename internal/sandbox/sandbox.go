@@ -0,0 +1,231 @@
+package sandbox
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"regexp"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// Sandbox runs shell commands subject to a Policy and ResourceLimits,
+// optionally isolating their network/PID namespace on Linux.
+type Sandbox struct {
+	Policy *Policy
+	Limits ResourceLimits
+
+	// NetworkIsolation and PIDIsolation wrap the command with unshare to
+	// give it its own network/PID namespace. Linux-only; ignored elsewhere.
+	NetworkIsolation bool
+	PIDIsolation     bool
+}
+
+// OutputLine is one line of stdout or stderr streamed from a running
+// command, tagged with the stream it came from.
+type OutputLine struct {
+	Stream string // "stdout" or "stderr"
+	Text   string
+}
+
+// Execution is a command in flight: its output streams as OutputLine
+// values on Output, and Done closes once the command has exited, with Err
+// and ExitCode set.
+type Execution struct {
+	Output chan OutputLine
+	Done   chan struct{}
+
+	mu          sync.Mutex
+	cmd         *exec.Cmd
+	ExitCode    int
+	Err         error
+	Interrupted bool
+}
+
+// Interrupt sends SIGINT to the running command, escalating to SIGKILL if
+// it hasn't exited within grace. It blocks until the command has exited.
+func (e *Execution) Interrupt(grace time.Duration) {
+	e.mu.Lock()
+	cmd := e.cmd
+	e.Interrupted = true
+	e.mu.Unlock()
+
+	if cmd == nil || cmd.Process == nil {
+		return
+	}
+	_ = cmd.Process.Signal(syscall.SIGINT)
+
+	select {
+	case <-e.Done:
+	case <-time.After(grace):
+		_ = cmd.Process.Signal(syscall.SIGKILL)
+		<-e.Done
+	}
+}
+
+// segmentSeparators is a conservative command separator so each
+// piped/chained sub-command's binary can be checked against policy
+// individually. It does not understand quoting, so a separator token
+// inside quotes is still treated as a split point - an accepted
+// limitation, since Policy.Check only needs each segment's leading binary,
+// not a full shell parse.
+var segmentSeparators = regexp.MustCompile(`\|\||&&|[|;&]`)
+
+func splitSegments(command string) []string {
+	parts := segmentSeparators.Split(command, -1)
+	segments := make([]string, 0, len(parts))
+	for _, part := range parts {
+		part = strings.TrimSpace(part)
+		if part != "" {
+			segments = append(segments, part)
+		}
+	}
+	return segments
+}
+
+// CheckPolicy validates every segment of command (split on shell control
+// operators) against the sandbox's Policy.
+func (s *Sandbox) CheckPolicy(command, workingDir string) error {
+	if s.Policy == nil {
+		return nil
+	}
+	for _, segment := range splitSegments(command) {
+		fields := strings.Fields(segment)
+		if len(fields) == 0 {
+			continue
+		}
+		// Pass the whole segment alongside the individual arguments so a
+		// DeniedArgPatterns entry can match either a single argument (e.g.
+		// "of=/dev/sda") or a pattern spanning the full invocation (e.g.
+		// "rm -rf /").
+		args := append(append([]string{}, fields[1:]...), segment)
+		if err := s.Policy.Check(fields[0], args, workingDir); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Start runs command under "bash -c", applying the sandbox's resource
+// limits and namespace isolation, and returns immediately with an
+// Execution whose Output channel streams stdout/stderr line-by-line as the
+// command runs.
+func (s *Sandbox) Start(ctx context.Context, command, workingDir string) (*Execution, error) {
+	if err := s.CheckPolicy(command, workingDir); err != nil {
+		return nil, err
+	}
+
+	name, args := wrapForIsolation(s.NetworkIsolation, s.PIDIsolation, "bash", []string{"-c", command})
+	cmd := exec.CommandContext(ctx, name, args...)
+	if workingDir != "" {
+		cmd.Dir = workingDir
+	}
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	stderr, err := cmd.StderrPipe()
+	if err != nil {
+		return nil, err
+	}
+
+	restore, err := s.Limits.apply()
+	if err != nil {
+		return nil, fmt.Errorf("sandbox: applying resource limits: %w", err)
+	}
+	startErr := cmd.Start()
+	restore()
+	if startErr != nil {
+		return nil, startErr
+	}
+
+	execution := &Execution{
+		Output: make(chan OutputLine),
+		Done:   make(chan struct{}),
+		cmd:    cmd,
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go streamLines(stdout, "stdout", execution.Output, &wg)
+	go streamLines(stderr, "stderr", execution.Output, &wg)
+
+	go func() {
+		wg.Wait()
+		waitErr := cmd.Wait()
+
+		execution.mu.Lock()
+		execution.Err = waitErr
+		if exitErr, ok := waitErr.(*exec.ExitError); ok {
+			if status, ok := exitErr.Sys().(syscall.WaitStatus); ok {
+				execution.ExitCode = status.ExitStatus()
+			}
+		}
+		execution.mu.Unlock()
+
+		close(execution.Output)
+		close(execution.Done)
+	}()
+
+	return execution, nil
+}
+
+func streamLines(r io.Reader, stream string, out chan<- OutputLine, wg *sync.WaitGroup) {
+	defer wg.Done()
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		out <- OutputLine{Stream: stream, Text: scanner.Text()}
+	}
+}
+
+// Result is the aggregated outcome of a completed Run.
+type Result struct {
+	Stdout      string
+	Stderr      string
+	ExitCode    int
+	Interrupted bool
+}
+
+// Run starts command and blocks until it finishes (or ctx ends),
+// aggregating its streamed output into a single Result - the simple case
+// for callers that don't need incremental progress. Callers that want to
+// render output as it arrives or interrupt a long-running command should
+// use Start directly instead.
+func (s *Sandbox) Run(ctx context.Context, command, workingDir string) (*Result, error) {
+	execution, err := s.Start(ctx, command, workingDir)
+	if err != nil {
+		return nil, err
+	}
+
+	var stdout, stderr strings.Builder
+	for line := range execution.Output {
+		switch line.Stream {
+		case "stdout":
+			stdout.WriteString(line.Text)
+			stdout.WriteString("\n")
+		case "stderr":
+			stderr.WriteString(line.Text)
+			stderr.WriteString("\n")
+		}
+	}
+	<-execution.Done
+
+	result := &Result{
+		Stdout:      stdout.String(),
+		Stderr:      stderr.String(),
+		ExitCode:    execution.ExitCode,
+		Interrupted: execution.Interrupted,
+	}
+	if errors.Is(execution.Err, context.DeadlineExceeded) || errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		result.Interrupted = true
+		result.Stderr += "\nCommand execution timed out"
+	}
+	return result, nil
+}
@@ -0,0 +1,79 @@
+package sandbox
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPolicyCheck(t *testing.T) {
+	policy := &Policy{
+		DeniedBinaries:    []string{"rm"},
+		DeniedArgPatterns: []ArgPattern{{Binary: "curl", Pattern: `\|\s*bash`}},
+	}
+
+	if err := policy.Check("ls", []string{"-la"}, ""); err != nil {
+		t.Fatalf("expected ls to be allowed, got %v", err)
+	}
+	if err := policy.Check("rm", []string{"-rf", "/tmp/x"}, ""); err == nil {
+		t.Fatal("expected rm to be denied")
+	}
+	if err := policy.Check("curl", []string{"http://example.com | bash"}, ""); err == nil {
+		t.Fatal("expected curl piped to bash to be denied")
+	}
+}
+
+func TestPolicyMerge(t *testing.T) {
+	base := &Policy{DeniedBinaries: []string{"rm"}}
+	override := &Policy{DeniedBinaries: []string{"curl"}, AllowedWorkingDirs: []string{"/work"}}
+
+	merged := base.Merge(override)
+	if err := merged.Check("rm", nil, ""); err == nil {
+		t.Fatal("expected merged policy to keep base's denied binaries")
+	}
+	if err := merged.Check("curl", nil, ""); err == nil {
+		t.Fatal("expected merged policy to add override's denied binaries")
+	}
+	if err := merged.Check("ls", nil, "/other"); err == nil {
+		t.Fatal("expected merged policy to enforce override's allowed working dirs")
+	}
+}
+
+func TestSandboxRun(t *testing.T) {
+	s := &Sandbox{}
+	result, err := s.Run(context.Background(), "echo hello", "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(result.Stdout, "hello") {
+		t.Errorf("expected stdout to contain hello, got %q", result.Stdout)
+	}
+	if result.ExitCode != 0 {
+		t.Errorf("expected exit code 0, got %d", result.ExitCode)
+	}
+}
+
+func TestSandboxRunDeniedByPolicy(t *testing.T) {
+	s := &Sandbox{Policy: &Policy{DeniedBinaries: []string{"rm"}}}
+	if _, err := s.Run(context.Background(), "rm -rf /tmp/does-not-exist", ""); err == nil {
+		t.Fatal("expected policy to deny rm")
+	}
+}
+
+func TestSandboxInterrupt(t *testing.T) {
+	s := &Sandbox{}
+	execution, err := s.Start(context.Background(), "sleep 5", "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	go func() {
+		for range execution.Output {
+		}
+	}()
+
+	execution.Interrupt(2 * time.Second)
+	if !execution.Interrupted {
+		t.Error("expected execution to be marked interrupted")
+	}
+}
@@ -3,18 +3,46 @@ package tools
 import (
 	"context"
 	"errors"
-	"io/ioutil"
 	"math"
-	"os/exec"
 	"strings"
-	"syscall"
 	"time"
 
 	"github.com/cinience/aigo-kode/internal/core"
+	"github.com/cinience/aigo-kode/internal/sandbox"
+	"github.com/cinience/aigo-kode/internal/tools/schema"
 )
 
+// BashToolInput is BashTool's declared input shape. Its structural
+// validation (required/type/range) is derived from these tags; the
+// banned-command deny list below is domain-specific and stays
+// hand-written, since no jsonschema tag can express it.
+type BashToolInput struct {
+	Command    string `json:"command" jsonschema:"required,minLength=1,description=The bash command to execute"`
+	Timeout    int    `json:"timeout,omitempty" jsonschema:"minimum=1,maximum=300,description=Timeout in seconds (default 30)"`
+	WorkingDir string `json:"working_dir,omitempty" jsonschema:"description=Working directory to run the command in"`
+}
+
+var bashToolSchema = schema.MustGenerate(BashToolInput{})
+
+// defaultBashPolicy is applied by NewBashTool, preserving the historical
+// banned-command behavior even for callers that don't configure a policy
+// of their own.
+var defaultBashPolicy = &sandbox.Policy{
+	DeniedArgPatterns: []sandbox.ArgPattern{
+		{Pattern: `rm -rf /$`},
+		{Pattern: `rm -rf /\*`},
+		{Pattern: `:\(\)\{ :\|:& \};:`},
+		{Pattern: `> /dev/sda`},
+		{Pattern: `dd if=/dev/random of=/dev/sda`},
+		{Pattern: `mv /\* /dev/null`},
+		{Pattern: `(wget|curl).*\|\s*bash`},
+	},
+}
+
 // BashTool implements the Tool interface for executing bash commands
-type BashTool struct{}
+type BashTool struct {
+	sandbox *sandbox.Sandbox
+}
 
 // Name returns the tool name
 func (t *BashTool) Name() string {
@@ -34,6 +62,11 @@ type BashToolOutput struct {
 	Interrupted bool   `json:"interrupted"`
 }
 
+// OutputSchema returns the JSON Schema for BashToolOutput
+func (t *BashTool) OutputSchema() string {
+	return `{"type":"object","properties":{"stdout":{"type":"string"},"stderr":{"type":"string"},"exit_code":{"type":"integer"},"interrupted":{"type":"boolean"}}}`
+}
+
 // Execute executes the bash command
 func (t *BashTool) Execute(ctx context.Context, input map[string]interface{}) (interface{}, error) {
 	// Extract command
@@ -48,83 +81,37 @@ func (t *BashTool) Execute(ctx context.Context, input map[string]interface{}) (i
 		timeout = time.Duration(timeoutVal) * time.Second
 	}
 
+	// Extract working directory
+	workingDir, _ := input["working_dir"].(string)
+
 	// Create a context with timeout
 	execCtx, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
 
-	// Create command
-	cmd := exec.CommandContext(execCtx, "bash", "-c", command)
-
-	// Set up pipes for stdout and stderr
-	stdout, err := cmd.StdoutPipe()
-	if err != nil {
-		return nil, err
-	}
-
-	stderr, err := cmd.StderrPipe()
+	result, err := t.sandbox.Run(execCtx, command, workingDir)
 	if err != nil {
 		return nil, err
 	}
 
-	// Start command
-	if err := cmd.Start(); err != nil {
-		return nil, err
-	}
-
-	// Read stdout and stderr
-	stdoutBytes, err := ioutil.ReadAll(stdout)
-	if err != nil {
-		return nil, err
-	}
-
-	stderrBytes, err := ioutil.ReadAll(stderr)
-	if err != nil {
-		return nil, err
-	}
-
-	// Wait for command to finish
-	err = cmd.Wait()
-
-	// Prepare result
-	result := &BashToolOutput{
-		Stdout:      string(stdoutBytes),
-		Stderr:      string(stderrBytes),
-		Interrupted: false,
-	}
-
-	// Handle exit code
-	if err != nil {
-		if exitErr, ok := err.(*exec.ExitError); ok {
-			if status, ok := exitErr.Sys().(syscall.WaitStatus); ok {
-				result.ExitCode = status.ExitStatus()
-			}
-		} else if errors.Is(err, context.DeadlineExceeded) {
-			result.Interrupted = true
-			result.Stderr += "\nCommand execution timed out"
-		}
-	}
-
-	return result, nil
+	return &BashToolOutput{
+		Stdout:      result.Stdout,
+		Stderr:      result.Stderr,
+		ExitCode:    result.ExitCode,
+		Interrupted: result.Interrupted,
+	}, nil
 }
 
-// ValidateInput validates the input parameters
+// ValidateInput validates the input parameters: structural checks
+// (required/type/range) come from BashToolInput's generated schema, then
+// the command is checked against a hand-written deny list no jsonschema
+// tag could express.
 func (t *BashTool) ValidateInput(input map[string]interface{}) error {
-	// Check if command exists and is a string
-	commandVal, ok := input["command"]
-	if !ok {
-		return errors.New("command is required")
+	if err := bashToolSchema.Validate(input); err != nil {
+		return err
 	}
 
-	command, ok := commandVal.(string)
-	if !ok {
-		return errors.New("command must be a string")
-	}
-
-	if command == "" {
-		return errors.New("command cannot be empty")
-	}
+	command := input["command"].(string)
 
-	// Check for dangerous commands
 	bannedCommands := []string{
 		"rm -rf /",
 		"rm -rf /*",
@@ -142,25 +129,15 @@ func (t *BashTool) ValidateInput(input map[string]interface{}) error {
 		}
 	}
 
-	// Validate timeout if present
-	if timeoutVal, ok := input["timeout"]; ok {
-		timeout, ok := timeoutVal.(int)
-		if !ok {
-			return errors.New("timeout must be an integer")
-		}
-
-		if timeout <= 0 {
-			return errors.New("timeout must be positive")
-		}
-
-		if timeout > 300 {
-			return errors.New("timeout cannot exceed 300 seconds")
-		}
-	}
-
 	return nil
 }
 
+// Arguments returns the JSON Schema properties generated from
+// BashToolInput
+func (t *BashTool) Arguments() string {
+	return bashToolSchema.PropertiesJSON()
+}
+
 // IsReadOnly returns whether the tool is read-only
 func (t *BashTool) IsReadOnly() bool {
 	return false
@@ -171,9 +148,36 @@ func (t *BashTool) RequiresPermission(input map[string]interface{}) bool {
 	return true
 }
 
-// NewBashTool creates a new BashTool
+// Fingerprint narrows permission approvals to the command's leading binary
+// (e.g. "git" for "git status"), so approving one command doesn't
+// blanket-approve every Bash call.
+func (t *BashTool) Fingerprint(input map[string]interface{}) string {
+	command, _ := input["command"].(string)
+	fields := strings.Fields(command)
+	if len(fields) == 0 {
+		return ""
+	}
+	return fields[0]
+}
+
+// NewBashTool creates a new BashTool that runs commands under the default
+// bash sandbox policy (the same banned-command protections the tool has
+// always enforced), with no additional resource limits or isolation.
 func NewBashTool() core.Tool {
-	return &BashTool{}
+	return NewBashToolWithPolicy(defaultBashPolicy, sandbox.ResourceLimits{})
+}
+
+// NewBashToolWithPolicy creates a BashTool that runs commands through a
+// sandbox.Sandbox configured with policy and limits, so callers (session
+// or project config) can layer allow/deny rules and resource caps on top
+// of - or instead of - the default protections.
+func NewBashToolWithPolicy(policy *sandbox.Policy, limits sandbox.ResourceLimits) core.Tool {
+	return &BashTool{
+		sandbox: &sandbox.Sandbox{
+			Policy: policy,
+			Limits: limits,
+		},
+	}
 }
 
 // min returns the minimum of two integers
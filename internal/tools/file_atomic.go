@@ -0,0 +1,79 @@
+package tools
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+
+	"github.com/spf13/afero"
+)
+
+// atomicWriteFile writes data to path by writing a sibling temp file in
+// the same directory, fsyncing it, then renaming it over path - so a
+// crash or context cancellation mid-write leaves the original file intact
+// instead of a half-written truncation.
+func atomicWriteFile(fs afero.Fs, path string, data []byte, perm os.FileMode) error {
+	dir := filepath.Dir(path)
+	if err := fs.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("creating directory: %w", err)
+	}
+
+	tmp, err := afero.TempFile(fs, dir, "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("creating temp file: %w", err)
+	}
+	tmpPath := tmp.Name()
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		fs.Remove(tmpPath)
+		return fmt.Errorf("writing temp file: %w", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		fs.Remove(tmpPath)
+		return fmt.Errorf("syncing temp file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		fs.Remove(tmpPath)
+		return fmt.Errorf("closing temp file: %w", err)
+	}
+	if err := fs.Chmod(tmpPath, perm); err != nil {
+		fs.Remove(tmpPath)
+		return fmt.Errorf("setting permissions: %w", err)
+	}
+
+	if err := renameOver(fs, tmpPath, path); err != nil {
+		fs.Remove(tmpPath)
+		return fmt.Errorf("renaming into place: %w", err)
+	}
+	return nil
+}
+
+// classifyWriteError reports err's message along with whether it was
+// specifically a permission error (os.IsPermission), as opposed to e.g. a
+// missing directory or a full disk - so callers can surface a distinct
+// PermissionDenied result instead of a generic error string.
+func classifyWriteError(err error) (message string, permissionDenied bool) {
+	if err == nil {
+		return "", false
+	}
+	return err.Error(), os.IsPermission(err)
+}
+
+// renameOver renames oldPath to newPath, replacing newPath if it already
+// exists. Rename already replaces an existing destination atomically on
+// every platform Go supports today, except that on Windows it can fail if
+// something else has newPath open; in that case we fall back to removing
+// the destination first and retrying, trading atomicity for the rename
+// succeeding at all.
+func renameOver(fs afero.Fs, oldPath, newPath string) error {
+	err := fs.Rename(oldPath, newPath)
+	if err == nil || runtime.GOOS != "windows" {
+		return err
+	}
+
+	fs.Remove(newPath)
+	return fs.Rename(oldPath, newPath)
+}
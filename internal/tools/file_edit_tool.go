@@ -3,14 +3,20 @@ package tools
 import (
 	"context"
 	"errors"
+	"fmt"
 	"os"
 	"strings"
+	"time"
+
+	"github.com/spf13/afero"
 
 	"github.com/cinience/aigo-kode/internal/core"
 )
 
 // FileEditTool implements the Tool interface for editing files
-type FileEditTool struct{}
+type FileEditTool struct {
+	fs afero.Fs
+}
 
 // Name returns the tool name
 func (t *FileEditTool) Name() string {
@@ -26,6 +32,10 @@ func (t *FileEditTool) Description() string {
 type FileEditToolOutput struct {
 	Success bool   `json:"success"`
 	Error   string `json:"error,omitempty"`
+	// PermissionDenied is set when Error was caused specifically by the
+	// OS denying the read or write (os.IsPermission), distinct from e.g.
+	// old_text not being found.
+	PermissionDenied bool `json:"permission_denied,omitempty"`
 }
 
 // Execute executes the file edit operation
@@ -47,11 +57,13 @@ func (t *FileEditTool) Execute(ctx context.Context, input map[string]interface{}
 	}
 
 	// Read file
-	content, err := os.ReadFile(filePath)
+	content, err := afero.ReadFile(t.fs, filePath)
 	if err != nil {
+		message, permissionDenied := classifyWriteError(err)
 		return &FileEditToolOutput{
-			Success: false,
-			Error:   "Failed to read file: " + err.Error(),
+			Success:          false,
+			Error:            "Failed to read file: " + message,
+			PermissionDenied: permissionDenied,
 		}, nil
 	}
 
@@ -64,12 +76,33 @@ func (t *FileEditTool) Execute(ctx context.Context, input map[string]interface{}
 		}, nil
 	}
 
-	// Write file
-	err = os.WriteFile(filePath, []byte(newContent), 0644)
-	if err != nil {
+	// Group edits sharing a transaction_id so FileRevertTool can undo a
+	// multi-file change together; edits that don't specify one default to
+	// being their own transaction.
+	transactionID, _ := input["transaction_id"].(string)
+	if transactionID == "" {
+		transactionID = fmt.Sprintf("txn-%d", time.Now().UnixNano())
+	}
+
+	// Back up the pre-edit content before overwriting, so FileRevertTool
+	// can restore it later.
+	if err := recordHistory(t.fs, filePath, transactionID, content); err != nil {
+		message, permissionDenied := classifyWriteError(err)
 		return &FileEditToolOutput{
-			Success: false,
-			Error:   "Failed to write file: " + err.Error(),
+			Success:          false,
+			Error:            "Failed to back up file: " + message,
+			PermissionDenied: permissionDenied,
+		}, nil
+	}
+
+	// Write file atomically: a crash or context cancellation mid-write
+	// leaves the original file intact instead of a half-written truncation.
+	if err := atomicWriteFile(t.fs, filePath, []byte(newContent), 0644); err != nil {
+		message, permissionDenied := classifyWriteError(err)
+		return &FileEditToolOutput{
+			Success:          false,
+			Error:            "Failed to write file: " + message,
+			PermissionDenied: permissionDenied,
 		}, nil
 	}
 
@@ -96,7 +129,7 @@ func (t *FileEditTool) ValidateInput(input map[string]interface{}) error {
 	}
 
 	// Check if file exists
-	if _, err := os.Stat(filePath); os.IsNotExist(err) {
+	if _, err := t.fs.Stat(filePath); os.IsNotExist(err) {
 		return errors.New("file does not exist")
 	}
 
@@ -126,6 +159,13 @@ func (t *FileEditTool) ValidateInput(input map[string]interface{}) error {
 		return errors.New("new_text must be a string")
 	}
 
+	// Validate transaction_id if present
+	if transactionIDVal, ok := input["transaction_id"]; ok {
+		if _, ok := transactionIDVal.(string); !ok {
+			return errors.New("transaction_id must be a string")
+		}
+	}
+
 	return nil
 }
 
@@ -142,10 +182,19 @@ func (t *FileEditTool) Arguments() string {
 		"new_text": {
 			"type": "string",
 			"description": "The new text to replace with"
+		},
+		"transaction_id": {
+			"type": "string",
+			"description": "Groups this edit with other edits sharing the same id so FileRevert can undo them together (default: a new id, reverted on its own)"
 		}
 	}`
 }
 
+// OutputSchema returns the JSON Schema for FileEditToolOutput
+func (t *FileEditTool) OutputSchema() string {
+	return `{"type":"object","properties":{"success":{"type":"boolean"},"error":{"type":"string"}}}`
+}
+
 // IsReadOnly returns whether the tool is read-only
 func (t *FileEditTool) IsReadOnly() bool {
 	return false
@@ -156,7 +205,15 @@ func (t *FileEditTool) RequiresPermission(input map[string]interface{}) bool {
 	return true
 }
 
-// NewFileEditTool creates a new FileEditTool
+// NewFileEditTool creates a new FileEditTool backed by the real OS
+// filesystem.
 func NewFileEditTool() core.Tool {
-	return &FileEditTool{}
+	return NewFileEditToolWithFS(afero.NewOsFs())
+}
+
+// NewFileEditToolWithFS creates a FileEditTool backed by fs, so a caller
+// can swap in an in-memory filesystem for tests, a chroot/basepath fs to
+// sandbox edits under a workspace root, or any other afero.Fs backend.
+func NewFileEditToolWithFS(fs afero.Fs) core.Tool {
+	return &FileEditTool{fs: fs}
 }
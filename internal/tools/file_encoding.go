@@ -0,0 +1,197 @@
+package tools
+
+import (
+	"encoding/binary"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// textEncoding is a charset FileReadTool/FileWriteTool can detect, decode
+// from, or encode to.
+type textEncoding string
+
+const (
+	encodingUTF8    textEncoding = "utf-8"
+	encodingUTF16LE textEncoding = "utf-16le"
+	encodingUTF16BE textEncoding = "utf-16be"
+	encodingLatin1  textEncoding = "latin1"
+	encodingGBK     textEncoding = "gbk"
+)
+
+// binarySniffLimit is how much of a file detectEncoding/looksBinary read
+// to make their call, so sniffing a multi-gigabyte file stays cheap.
+const binarySniffLimit = 8192
+
+// detectEncoding guesses sample's charset: a BOM is trusted outright;
+// failing that, BOM-less UTF-16 is recognized when the sample is ASCII
+// text (one zero byte in every code unit); failing that, valid UTF-8
+// wins; failing that, a density heuristic looks
+// for GBK's characteristic lead/trail byte pairing before falling back to
+// Latin-1, which (mapping every byte to the identical code point) never
+// itself fails to decode. GBK is reported even though decodeToUTF8 can't
+// decode it, so a caller gets an explicit unsupported-encoding error instead
+// of silently mistaking GBK text for Latin-1 garbage.
+func detectEncoding(sample []byte) textEncoding {
+	switch {
+	case len(sample) >= 2 && sample[0] == 0xFF && sample[1] == 0xFE:
+		return encodingUTF16LE
+	case len(sample) >= 2 && sample[0] == 0xFE && sample[1] == 0xFF:
+		return encodingUTF16BE
+	case len(sample) >= 3 && sample[0] == 0xEF && sample[1] == 0xBB && sample[2] == 0xBF:
+		return encodingUTF8
+	}
+
+	if len(sample) >= 2 && len(sample)%2 == 0 {
+		le, be := true, true
+		for i := 0; i < len(sample); i += 2 {
+			le = le && sample[i] != 0 && sample[i+1] == 0
+			be = be && sample[i] == 0 && sample[i+1] != 0
+		}
+		if le {
+			return encodingUTF16LE
+		}
+		if be {
+			return encodingUTF16BE
+		}
+	}
+
+	if utf8.Valid(sample) {
+		return encodingUTF8
+	}
+
+	if looksLikeGBK(sample) {
+		return encodingGBK
+	}
+
+	return encodingLatin1
+}
+
+// looksLikeGBK reports whether a large fraction of sample's non-ASCII
+// bytes form valid GBK lead/trail byte pairs (lead 0x81-0xFE, trail
+// 0x40-0xFE excluding 0x7F).
+func looksLikeGBK(sample []byte) bool {
+	pairs, candidates := 0, 0
+	for i := 0; i < len(sample); i++ {
+		b := sample[i]
+		if b < 0x81 || b == 0xFF {
+			continue
+		}
+		candidates++
+		if i+1 >= len(sample) {
+			continue
+		}
+		trail := sample[i+1]
+		if trail == 0x7F || trail < 0x40 || trail == 0xFF {
+			continue
+		}
+		pairs++
+		i++ // consumed as part of this pair
+	}
+	return candidates > 0 && float64(pairs)/float64(candidates) > 0.9
+}
+
+// stripBOM removes a leading UTF-8 byte-order mark, if present.
+func stripBOM(data []byte) []byte {
+	if len(data) >= 3 && data[0] == 0xEF && data[1] == 0xBB && data[2] == 0xBF {
+		return data[3:]
+	}
+	return data
+}
+
+// decodeToUTF8 transcodes data (assumed to be enc) to a UTF-8 string. It
+// returns unsupportedEncodingError for a charset detectEncoding can
+// recognize but this package can't actually decode (currently GBK - see
+// detectEncoding), rather than silently
+// returning corrupted text.
+func decodeToUTF8(data []byte, enc textEncoding) (string, error) {
+	switch enc {
+	case encodingUTF16LE, encodingUTF16BE:
+		return decodeUTF16(data, enc == encodingUTF16LE), nil
+	case encodingGBK:
+		return "", unsupportedEncodingError(enc)
+	case encodingLatin1:
+		return decodeLatin1(data), nil
+	default:
+		return string(stripBOM(data)), nil
+	}
+}
+
+func decodeUTF16(data []byte, littleEndian bool) string {
+	if len(data) >= 2 && ((littleEndian && data[0] == 0xFF && data[1] == 0xFE) ||
+		(!littleEndian && data[0] == 0xFE && data[1] == 0xFF)) {
+		data = data[2:]
+	}
+
+	order := binary.ByteOrder(binary.BigEndian)
+	if littleEndian {
+		order = binary.LittleEndian
+	}
+
+	units := make([]uint16, len(data)/2)
+	for i := range units {
+		units[i] = order.Uint16(data[i*2:])
+	}
+	return string(utf16.Decode(units))
+}
+
+func decodeLatin1(data []byte) string {
+	var b strings.Builder
+	b.Grow(len(data))
+	for _, c := range data {
+		b.WriteRune(rune(c))
+	}
+	return b.String()
+}
+
+// encodeFromUTF8 converts s (well-formed UTF-8) into enc's byte
+// representation for writing to disk. GBK is intentionally unsupported,
+// since this package doesn't embed a GBK code-page table (see decodeToUTF8).
+func encodeFromUTF8(s string, enc textEncoding) ([]byte, error) {
+	switch enc {
+	case encodingUTF16LE, encodingUTF16BE:
+		units := utf16.Encode([]rune(s))
+		order := binary.ByteOrder(binary.BigEndian)
+		if enc == encodingUTF16LE {
+			order = binary.LittleEndian
+		}
+		out := make([]byte, len(units)*2)
+		for i, u := range units {
+			order.PutUint16(out[i*2:], u)
+		}
+		return out, nil
+	case encodingLatin1:
+		out := make([]byte, 0, len(s))
+		for _, r := range s {
+			if r > 0xFF {
+				r = '?'
+			}
+			out = append(out, byte(r))
+		}
+		return out, nil
+	case encodingUTF8, "":
+		return []byte(s), nil
+	default:
+		return nil, unsupportedEncodingError(enc)
+	}
+}
+
+type unsupportedEncodingError textEncoding
+
+func (e unsupportedEncodingError) Error() string {
+	return "encoding " + string(e) + " is not supported"
+}
+
+// looksBinary applies a null-byte heuristic to sample (conventionally the
+// first binarySniffLimit bytes of a file): the presence of a NUL byte is a
+// strong signal the file isn't text, since none of the encodings above
+// except UTF-16 produce one from ordinary text content; FileReadTool rules
+// out UTF-16 before calling it.
+func looksBinary(sample []byte) bool {
+	for _, b := range sample {
+		if b == 0 {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,127 @@
+package tools
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFileReadToolDetectsBinary(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	assert.NoError(t, afero.WriteFile(fs, "/workspace/blob.bin", []byte("abc\x00def"), 0644))
+
+	readTool := NewFileReadToolWithFS(fs)
+	result, err := readTool.Execute(context.Background(), map[string]interface{}{
+		"file_path": "/workspace/blob.bin",
+	})
+	assert.NoError(t, err)
+	readResult := result.(*FileReadToolOutput)
+	assert.Equal(t, "binary", readResult.Type)
+	assert.NotEmpty(t, readResult.Content)
+	assert.NotEmpty(t, readResult.HexPreview)
+}
+
+func TestFileReadToolLineRange(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	assert.NoError(t, afero.WriteFile(fs, "/workspace/lines.txt", []byte("one\ntwo\nthree\nfour\n"), 0644))
+
+	readTool := NewFileReadToolWithFS(fs)
+	result, err := readTool.Execute(context.Background(), map[string]interface{}{
+		"file_path":  "/workspace/lines.txt",
+		"line_range": "2-3",
+	})
+	assert.NoError(t, err)
+	readResult := result.(*FileReadToolOutput)
+	assert.Equal(t, "text", readResult.Type)
+	assert.Equal(t, "two\nthree", readResult.Content)
+}
+
+func TestFileWriteAndReadRoundTripUTF16LE(t *testing.T) {
+	fs := afero.NewMemMapFs()
+
+	writeTool := NewFileWriteToolWithFS(fs)
+	result, err := writeTool.Execute(context.Background(), map[string]interface{}{
+		"file_path":        "/workspace/utf16.txt",
+		"content":          "hello",
+		"trailing_newline": false,
+		"encoding":         "utf-16le",
+	})
+	assert.NoError(t, err)
+	assert.True(t, result.(*FileWriteToolOutput).Success)
+
+	readTool := NewFileReadToolWithFS(fs)
+	result, err = readTool.Execute(context.Background(), map[string]interface{}{
+		"file_path": "/workspace/utf16.txt",
+	})
+	assert.NoError(t, err)
+	readResult := result.(*FileReadToolOutput)
+	assert.Equal(t, "text", readResult.Type)
+	assert.Equal(t, "utf-16le", readResult.Encoding)
+	assert.Equal(t, "hello", readResult.Content)
+}
+
+func TestFileReadToolDecodesLatin1(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	// 0xE9 is "é" in Latin-1 but not valid UTF-8 on its own.
+	assert.NoError(t, afero.WriteFile(fs, "/workspace/latin1.txt", []byte("caf\xe9"), 0644))
+
+	readTool := NewFileReadToolWithFS(fs)
+	result, err := readTool.Execute(context.Background(), map[string]interface{}{
+		"file_path": "/workspace/latin1.txt",
+	})
+	assert.NoError(t, err)
+	readResult := result.(*FileReadToolOutput)
+	assert.Equal(t, "text", readResult.Type)
+	assert.Equal(t, "latin1", readResult.Encoding)
+	assert.Equal(t, "café", readResult.Content)
+}
+
+func TestFileWriteToolLineEndings(t *testing.T) {
+	fs := afero.NewMemMapFs()
+
+	writeTool := NewFileWriteToolWithFS(fs)
+	result, err := writeTool.Execute(context.Background(), map[string]interface{}{
+		"file_path":        "/workspace/crlf.txt",
+		"content":          "one\ntwo",
+		"trailing_newline": false,
+		"line_endings":     "crlf",
+	})
+	assert.NoError(t, err)
+	assert.True(t, result.(*FileWriteToolOutput).Success)
+
+	content, err := afero.ReadFile(fs, "/workspace/crlf.txt")
+	assert.NoError(t, err)
+	assert.Equal(t, "one\r\ntwo", string(content))
+}
+
+func TestFileReadToolRejectsUndecodableGBK(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	// Lead/trail byte pairs detectEncoding's heuristic recognizes as GBK,
+	// but which this package has no code-page table to actually decode.
+	gbkLike := bytes.Repeat([]byte{0x81, 0x40}, 10)
+	assert.NoError(t, afero.WriteFile(fs, "/workspace/gbk.txt", gbkLike, 0644))
+
+	readTool := NewFileReadToolWithFS(fs)
+	result, err := readTool.Execute(context.Background(), map[string]interface{}{
+		"file_path": "/workspace/gbk.txt",
+	})
+	assert.NoError(t, err)
+	readResult := result.(*FileReadToolOutput)
+	assert.Equal(t, "error", readResult.Type)
+	assert.NotEmpty(t, readResult.Error)
+	assert.Empty(t, readResult.Content)
+}
+
+func TestFileWriteToolRejectsUnsupportedEncoding(t *testing.T) {
+	writeTool := NewFileWriteToolWithFS(afero.NewMemMapFs())
+
+	err := writeTool.ValidateInput(map[string]interface{}{
+		"file_path": "/workspace/gbk.txt",
+		"content":   "hello",
+		"encoding":  "gbk",
+	})
+	assert.Error(t, err)
+}
@@ -0,0 +1,87 @@
+package tools
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"time"
+
+	"github.com/spf13/afero"
+)
+
+// historyDir is where FileEditTool keeps pre-edit backups and
+// FileRevertTool restores them from, relative to the process's working
+// directory.
+const historyDir = ".aigo/history"
+
+const historyIndexPath = historyDir + "/index.json"
+
+// historyEntry records one edit's pre-image so FileRevertTool can restore
+// it later. Entries sharing a TransactionID are reverted together.
+type historyEntry struct {
+	ID            string `json:"id"`
+	TransactionID string `json:"transaction_id"`
+	FilePath      string `json:"file_path"`
+	BackupPath    string `json:"backup_path"`
+	Timestamp     string `json:"timestamp"`
+}
+
+// recordHistory backs up content as filePath's pre-image under historyDir
+// and appends an entry for it to the history index.
+func recordHistory(fs afero.Fs, filePath, transactionID string, content []byte) error {
+	id := fmt.Sprintf("%d", time.Now().UnixNano())
+	backupPath := path.Join(historyDir, id+".bak")
+	if err := atomicWriteFile(fs, backupPath, content, 0644); err != nil {
+		return fmt.Errorf("backing up pre-edit content: %w", err)
+	}
+
+	entries, err := readHistoryIndex(fs)
+	if err != nil {
+		return err
+	}
+	entries = append(entries, historyEntry{
+		ID:            id,
+		TransactionID: transactionID,
+		FilePath:      filePath,
+		BackupPath:    backupPath,
+		Timestamp:     time.Now().UTC().Format(time.RFC3339Nano),
+	})
+	return writeHistoryIndex(fs, entries)
+}
+
+func readHistoryIndex(fs afero.Fs) ([]historyEntry, error) {
+	data, err := afero.ReadFile(fs, historyIndexPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("reading history index: %w", err)
+	}
+
+	var entries []historyEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("parsing history index: %w", err)
+	}
+	return entries, nil
+}
+
+func writeHistoryIndex(fs afero.Fs, entries []historyEntry) error {
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding history index: %w", err)
+	}
+	if err := atomicWriteFile(fs, historyIndexPath, data, 0644); err != nil {
+		return fmt.Errorf("writing history index: %w", err)
+	}
+	return nil
+}
+
+// lastTransactionID returns the TransactionID of the most recently
+// recorded entry, or "" if history is empty.
+func lastTransactionID(entries []historyEntry) string {
+	if len(entries) == 0 {
+		return ""
+	}
+	return entries[len(entries)-1].TransactionID
+}
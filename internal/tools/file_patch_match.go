@@ -0,0 +1,296 @@
+package tools
+
+import (
+	"fmt"
+	"strings"
+)
+
+// hunkMatch is where a patchHunk was located in the file's original lines.
+type hunkMatch struct {
+	hunk  patchHunk
+	start int // 0-based index into lines where OldLines begins
+}
+
+// locateHunks finds where each hunk's old_lines best matches within
+// lines, preferring an exact match, falling back to the closest
+// whitespace-tolerant fuzzy match above threshold. Hunks that can't be
+// placed unambiguously are returned as rejected instead.
+func locateHunks(lines []string, hunks []patchHunk, threshold float64) ([]hunkMatch, []RejectedHunk) {
+	var matches []hunkMatch
+	var rejected []RejectedHunk
+
+	for _, hunk := range hunks {
+		start, reason := locateHunk(lines, hunk, threshold)
+		if reason != "" {
+			rejected = append(rejected, RejectedHunk{
+				Index:  hunk.Index,
+				Reason: reason,
+				Patch:  rejectPatch(hunk, reason),
+			})
+			continue
+		}
+		matches = append(matches, hunkMatch{hunk: hunk, start: start})
+	}
+
+	return matches, rejected
+}
+
+// locateHunk returns the 0-based start line of hunk.OldLines within
+// lines, or a non-empty rejection reason if it can't be placed.
+func locateHunk(lines []string, hunk patchHunk, threshold float64) (int, string) {
+	windowSize := len(hunk.OldLines)
+	if windowSize > len(lines) {
+		return 0, "old_lines is longer than the file"
+	}
+
+	// A start_line hint is tried first, and only as an exact match - a
+	// hint that no longer lines up exactly is a sign the file moved on,
+	// so we fall back to searching the whole file instead of trusting it.
+	if hunk.StartLine > 0 {
+		idx := hunk.StartLine - 1
+		if idx >= 0 && idx+windowSize <= len(lines) && linesEqual(lines[idx:idx+windowSize], hunk.OldLines) {
+			return idx, ""
+		}
+	}
+
+	bestScore := -1.0
+	bestStart := -1
+	tie := false
+	exactScore, exactStart, exactTie := -1.0, -1, false
+
+	for start := 0; start+windowSize <= len(lines); start++ {
+		window := lines[start : start+windowSize]
+		if linesEqual(window, hunk.OldLines) {
+			// An exact match elsewhere in the file is unambiguous only if
+			// it's the only one (or its context tells it apart); keep
+			// scanning to detect duplicates.
+			exactScoreHere := adjustForContext(lines, start, windowSize, hunk, 1)
+			if exactScoreHere > exactScore {
+				exactScore, exactStart, exactTie = exactScoreHere, start, false
+			} else if exactScoreHere == exactScore {
+				exactTie = true
+			}
+			continue
+		}
+
+		score := windowSimilarity(window, hunk.OldLines)
+		score = adjustForContext(lines, start, windowSize, hunk, score)
+
+		if score > bestScore {
+			bestScore = score
+			bestStart = start
+			tie = false
+		} else if score == bestScore && bestScore >= 0 {
+			tie = true
+		}
+	}
+
+	if exactStart != -1 {
+		if exactTie {
+			return 0, "ambiguous match: multiple candidate locations scored equally"
+		}
+		return exactStart, ""
+	}
+	if bestStart == -1 || bestScore < threshold {
+		return 0, "no matching context found"
+	}
+	if tie {
+		return 0, "ambiguous match: multiple candidate locations scored equally"
+	}
+
+	return bestStart, ""
+}
+
+// windowSimilarity is the average per-line similarity between a and b,
+// which must be the same length.
+func windowSimilarity(a, b []string) float64 {
+	if len(a) == 0 {
+		return 1
+	}
+	total := 0.0
+	for i := range a {
+		total += lineSimilarity(a[i], b[i])
+	}
+	return total / float64(len(a))
+}
+
+// adjustForContext nudges score using how well context_before/
+// context_after line up immediately outside the candidate window, so two
+// equally-similar windows elsewhere in the file can be told apart.
+func adjustForContext(lines []string, start, windowSize int, hunk patchHunk, score float64) float64 {
+	if len(hunk.ContextBefore) == 0 && len(hunk.ContextAfter) == 0 {
+		return score
+	}
+
+	matches, total := 0, 0
+	if n := len(hunk.ContextBefore); n > 0 {
+		from := start - n
+		if from >= 0 {
+			total += n
+			if linesEqual(lines[from:start], hunk.ContextBefore) {
+				matches += n
+			}
+		}
+	}
+	if n := len(hunk.ContextAfter); n > 0 {
+		to := start + windowSize + n
+		if to <= len(lines) {
+			total += n
+			if linesEqual(lines[start+windowSize:to], hunk.ContextAfter) {
+				matches += n
+			}
+		}
+	}
+	if total == 0 {
+		return score
+	}
+
+	// Blend the context match ratio in as a tiebreaker without letting it
+	// override a genuinely bad body match.
+	contextScore := float64(matches) / float64(total)
+	return score*0.8 + contextScore*0.2
+}
+
+// lineSimilarity scores how alike two lines are after trimming
+// surrounding whitespace, as 1 - (edit distance / longer line length).
+// Identical lines (including both blank) score 1.
+func lineSimilarity(a, b string) float64 {
+	a = strings.TrimSpace(a)
+	b = strings.TrimSpace(b)
+	if a == b {
+		return 1
+	}
+	maxLen := len(a)
+	if len(b) > maxLen {
+		maxLen = len(b)
+	}
+	if maxLen == 0 {
+		return 1
+	}
+	return 1 - float64(levenshtein(a, b))/float64(maxLen)
+}
+
+func linesEqual(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// levenshtein is the classic edit-distance DP, operating on runes so
+// multi-byte characters count as one edit each.
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	if len(ra) == 0 {
+		return len(rb)
+	}
+	if len(rb) == 0 {
+		return len(ra)
+	}
+
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			del := prev[j] + 1
+			ins := curr[j-1] + 1
+			sub := prev[j-1] + cost
+			curr[j] = min3(del, ins, sub)
+		}
+		prev, curr = curr, prev
+	}
+
+	return prev[len(rb)]
+}
+
+func min3(a, b, c int) int {
+	m := a
+	if b < m {
+		m = b
+	}
+	if c < m {
+		m = c
+	}
+	return m
+}
+
+// applyHunks replaces each matched hunk's window with its new_lines,
+// applying in descending position order so earlier replacements don't
+// shift the offsets later ones were located at, and renders a unified
+// diff covering every hunk in its original (ascending) order.
+func applyHunks(filePath string, lines []string, matches []hunkMatch) ([]string, string) {
+	ordered := append([]hunkMatch(nil), matches...)
+	sortMatchesByStart(ordered)
+
+	var diffParts []string
+	lineDelta := 0
+	for _, m := range ordered {
+		diffParts = append(diffParts, renderHunkDiff(m, lineDelta))
+		lineDelta += len(m.hunk.NewLines) - len(m.hunk.OldLines)
+	}
+
+	result := append([]string(nil), lines...)
+	for i := len(ordered) - 1; i >= 0; i-- {
+		m := ordered[i]
+		end := m.start + len(m.hunk.OldLines)
+		result = append(result[:m.start], append(append([]string(nil), m.hunk.NewLines...), result[end:]...)...)
+	}
+
+	header := fmt.Sprintf("--- a/%s\n+++ b/%s\n", filePath, filePath)
+	return result, header + strings.Join(diffParts, "")
+}
+
+func sortMatchesByStart(matches []hunkMatch) {
+	for i := 1; i < len(matches); i++ {
+		for j := i; j > 0 && matches[j-1].start > matches[j].start; j-- {
+			matches[j-1], matches[j] = matches[j], matches[j-1]
+		}
+	}
+}
+
+// renderHunkDiff renders one hunk as a "@@ -old +new @@" block. newStart
+// accounts for lineDelta already introduced by earlier (lower-numbered)
+// hunks in the same file.
+func renderHunkDiff(m hunkMatch, lineDelta int) string {
+	oldStart := m.start + 1
+	newStart := oldStart + lineDelta
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "@@ -%d,%d +%d,%d @@\n", oldStart, len(m.hunk.OldLines), newStart, len(m.hunk.NewLines))
+	for _, l := range m.hunk.OldLines {
+		fmt.Fprintf(&b, "-%s\n", l)
+	}
+	for _, l := range m.hunk.NewLines {
+		fmt.Fprintf(&b, "+%s\n", l)
+	}
+	return b.String()
+}
+
+// rejectPatch renders a hunk that couldn't be applied in the style of a
+// traditional `patch` .rej file.
+func rejectPatch(hunk patchHunk, reason string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# hunk %d rejected: %s\n", hunk.Index, reason)
+	fmt.Fprintf(&b, "@@ old_lines @@\n")
+	for _, l := range hunk.OldLines {
+		fmt.Fprintf(&b, "-%s\n", l)
+	}
+	for _, l := range hunk.NewLines {
+		fmt.Fprintf(&b, "+%s\n", l)
+	}
+	return b.String()
+}
@@ -0,0 +1,327 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/afero"
+
+	"github.com/cinience/aigo-kode/internal/core"
+)
+
+// defaultFuzzThreshold is how similar a candidate window of lines must be
+// to a hunk's old_lines (as an average per-line similarity score in
+// [0,1], see lineSimilarity) to be accepted as a match when no exact match
+// exists.
+const defaultFuzzThreshold = 0.75
+
+// FilePatchTool implements the Tool interface for applying a set of
+// line-anchored, whitespace-tolerant hunks to a file - a structured
+// alternative to FileEdit's single strings.Replace(old, new, -1), which
+// rewrites every occurrence of old_text and has no tolerance for
+// whitespace drift between what the agent remembers and what's on disk.
+type FilePatchTool struct {
+	fs afero.Fs
+}
+
+// Name returns the tool name
+func (t *FilePatchTool) Name() string {
+	return "FilePatch"
+}
+
+// Description returns the tool description
+func (t *FilePatchTool) Description() string {
+	return "Applies one or more line-anchored hunks to a file, tolerating minor whitespace/indentation drift, with a dry-run mode that previews the diff without writing"
+}
+
+// patchHunk is one hunk as given in a FilePatch call's "hunks" input.
+type patchHunk struct {
+	Index         int
+	OldLines      []string
+	NewLines      []string
+	StartLine     int // 1-based hint, 0 if not given
+	ContextBefore []string
+	ContextAfter  []string
+}
+
+// RejectedHunk describes a hunk FilePatchTool could not apply, in a form
+// similar to the ".rej" files traditional `patch` leaves behind.
+type RejectedHunk struct {
+	Index  int    `json:"index"`
+	Reason string `json:"reason"`
+	Patch  string `json:"patch"`
+}
+
+// FilePatchToolOutput defines the output structure for FilePatchTool
+type FilePatchToolOutput struct {
+	Success  bool           `json:"success"`
+	DryRun   bool           `json:"dry_run,omitempty"`
+	Applied  []int          `json:"applied,omitempty"`
+	Rejected []RejectedHunk `json:"rejected,omitempty"`
+	Diff     string         `json:"diff,omitempty"`
+	Error    string         `json:"error,omitempty"`
+	// PermissionDenied is set when Error was caused specifically by the OS
+	// denying the read or write (os.IsPermission), distinct from e.g. a
+	// hunk that couldn't be located.
+	PermissionDenied bool `json:"permission_denied,omitempty"`
+}
+
+// OutputSchema returns the JSON Schema for FilePatchToolOutput
+func (t *FilePatchTool) OutputSchema() string {
+	return `{"type":"object","properties":{"success":{"type":"boolean"},"dry_run":{"type":"boolean"},"applied":{"type":"array","items":{"type":"number"}},"rejected":{"type":"array","items":{"type":"object"}},"diff":{"type":"string"},"error":{"type":"string"}}}`
+}
+
+// Execute executes the patch operation
+func (t *FilePatchTool) Execute(ctx context.Context, input map[string]interface{}) (interface{}, error) {
+	filePath, ok := input["file_path"].(string)
+	if !ok || filePath == "" {
+		return nil, errors.New("file_path is required and must be a string")
+	}
+
+	hunks, err := parsePatchHunks(input["hunks"])
+	if err != nil {
+		return nil, err
+	}
+	if len(hunks) == 0 {
+		return nil, errors.New("hunks is required and must be a non-empty array")
+	}
+
+	threshold := defaultFuzzThreshold
+	if thresholdVal, ok := input["fuzz_threshold"].(float64); ok && thresholdVal > 0 {
+		threshold = thresholdVal
+	}
+
+	dryRun, _ := input["dry_run"].(bool)
+
+	content, err := afero.ReadFile(t.fs, filePath)
+	if err != nil {
+		message, permissionDenied := classifyWriteError(err)
+		return &FilePatchToolOutput{Success: false, Error: "Failed to read file: " + message, PermissionDenied: permissionDenied}, nil
+	}
+
+	trailingNewline := strings.HasSuffix(string(content), "\n")
+	lines := strings.Split(strings.TrimSuffix(string(content), "\n"), "\n")
+
+	matches, rejected := locateHunks(lines, hunks, threshold)
+	if len(matches) == 0 {
+		return &FilePatchToolOutput{
+			Success:  false,
+			Rejected: rejected,
+			Error:    "No hunks could be applied",
+		}, nil
+	}
+
+	newLines, diff := applyHunks(filePath, lines, matches)
+
+	applied := make([]int, len(matches))
+	for i, m := range matches {
+		applied[i] = m.hunk.Index
+	}
+
+	output := &FilePatchToolOutput{
+		Success:  len(rejected) == 0,
+		DryRun:   dryRun,
+		Applied:  applied,
+		Rejected: rejected,
+		Diff:     diff,
+	}
+
+	if dryRun {
+		return output, nil
+	}
+
+	newContent := strings.Join(newLines, "\n")
+	if trailingNewline {
+		newContent += "\n"
+	}
+
+	transactionID, _ := input["transaction_id"].(string)
+	if transactionID == "" {
+		transactionID = fmt.Sprintf("txn-%d", time.Now().UnixNano())
+	}
+	if err := recordHistory(t.fs, filePath, transactionID, content); err != nil {
+		message, permissionDenied := classifyWriteError(err)
+		return &FilePatchToolOutput{Success: false, Error: "Failed to back up file: " + message, PermissionDenied: permissionDenied}, nil
+	}
+
+	if err := atomicWriteFile(t.fs, filePath, []byte(newContent), 0644); err != nil {
+		message, permissionDenied := classifyWriteError(err)
+		return &FilePatchToolOutput{Success: false, Error: "Failed to write file: " + message, PermissionDenied: permissionDenied}, nil
+	}
+
+	return output, nil
+}
+
+// ValidateInput validates the input parameters
+func (t *FilePatchTool) ValidateInput(input map[string]interface{}) error {
+	filePathVal, ok := input["file_path"]
+	if !ok {
+		return errors.New("file_path is required")
+	}
+	filePath, ok := filePathVal.(string)
+	if !ok || filePath == "" {
+		return errors.New("file_path must be a non-empty string")
+	}
+
+	if _, err := t.fs.Stat(filePath); err != nil {
+		return errors.New("file does not exist")
+	}
+
+	if _, err := parsePatchHunks(input["hunks"]); err != nil {
+		return err
+	}
+	if hunksVal, _ := input["hunks"].([]interface{}); len(hunksVal) == 0 {
+		return errors.New("hunks is required and must be a non-empty array")
+	}
+
+	if v, ok := input["fuzz_threshold"]; ok {
+		if _, ok := v.(float64); !ok {
+			return errors.New("fuzz_threshold must be a number")
+		}
+	}
+	if v, ok := input["dry_run"]; ok {
+		if _, ok := v.(bool); !ok {
+			return errors.New("dry_run must be a boolean")
+		}
+	}
+	if v, ok := input["transaction_id"]; ok {
+		if _, ok := v.(string); !ok {
+			return errors.New("transaction_id must be a string")
+		}
+	}
+
+	return nil
+}
+
+func (t *FilePatchTool) Arguments() string {
+	return `{
+		"file_path": {
+			"type": "string",
+			"description": "The path to the file to patch"
+		},
+		"hunks": {
+			"type": "array",
+			"description": "The hunks to apply, each as {old_lines, new_lines, start_line, context_before, context_after}",
+			"items": {
+				"type": "object",
+				"properties": {
+					"old_lines": {"type": "array", "items": {"type": "string"}, "description": "Lines to find and replace"},
+					"new_lines": {"type": "array", "items": {"type": "string"}, "description": "Lines to replace them with"},
+					"start_line": {"type": "number", "description": "1-based hint for where old_lines starts"},
+					"context_before": {"type": "array", "items": {"type": "string"}, "description": "Lines expected immediately before old_lines, used to disambiguate matches"},
+					"context_after": {"type": "array", "items": {"type": "string"}, "description": "Lines expected immediately after old_lines, used to disambiguate matches"}
+				}
+			}
+		},
+		"fuzz_threshold": {
+			"type": "number",
+			"description": "Minimum average per-line similarity (0-1) to accept a non-exact match (default: 0.75)"
+		},
+		"dry_run": {
+			"type": "boolean",
+			"description": "If true, return the resulting diff without writing to disk"
+		},
+		"transaction_id": {
+			"type": "string",
+			"description": "Groups this patch with other edits sharing the same id so FileRevert can undo them together"
+		}
+	}`
+}
+
+// IsReadOnly returns whether the tool is read-only
+func (t *FilePatchTool) IsReadOnly() bool {
+	return false
+}
+
+// RequiresPermission checks if permission is needed
+func (t *FilePatchTool) RequiresPermission(input map[string]interface{}) bool {
+	dryRun, _ := input["dry_run"].(bool)
+	return !dryRun
+}
+
+// NewFilePatchTool creates a new FilePatchTool backed by the real OS
+// filesystem.
+func NewFilePatchTool() core.Tool {
+	return NewFilePatchToolWithFS(afero.NewOsFs())
+}
+
+// NewFilePatchToolWithFS creates a FilePatchTool backed by fs, so a caller
+// can swap in an in-memory filesystem for tests, a chroot/basepath fs to
+// sandbox patches under a workspace root, or any other afero.Fs backend.
+func NewFilePatchToolWithFS(fs afero.Fs) core.Tool {
+	return &FilePatchTool{fs: fs}
+}
+
+// parsePatchHunks converts the raw "hunks" input value into patchHunks.
+func parsePatchHunks(raw interface{}) ([]patchHunk, error) {
+	rawHunks, ok := raw.([]interface{})
+	if !ok {
+		return nil, errors.New("hunks must be an array")
+	}
+
+	hunks := make([]patchHunk, 0, len(rawHunks))
+	for i, rawHunk := range rawHunks {
+		hunkMap, ok := rawHunk.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("hunks[%d] must be an object", i)
+		}
+
+		oldLines, err := stringSlice(hunkMap["old_lines"])
+		if err != nil {
+			return nil, fmt.Errorf("hunks[%d].old_lines: %w", i, err)
+		}
+		if len(oldLines) == 0 {
+			return nil, fmt.Errorf("hunks[%d].old_lines is required and must be non-empty", i)
+		}
+		newLines, err := stringSlice(hunkMap["new_lines"])
+		if err != nil {
+			return nil, fmt.Errorf("hunks[%d].new_lines: %w", i, err)
+		}
+		contextBefore, err := stringSlice(hunkMap["context_before"])
+		if err != nil {
+			return nil, fmt.Errorf("hunks[%d].context_before: %w", i, err)
+		}
+		contextAfter, err := stringSlice(hunkMap["context_after"])
+		if err != nil {
+			return nil, fmt.Errorf("hunks[%d].context_after: %w", i, err)
+		}
+
+		startLine := 0
+		if startLineVal, ok := hunkMap["start_line"].(float64); ok {
+			startLine = int(startLineVal)
+		}
+
+		hunks = append(hunks, patchHunk{
+			Index:         i,
+			OldLines:      oldLines,
+			NewLines:      newLines,
+			StartLine:     startLine,
+			ContextBefore: contextBefore,
+			ContextAfter:  contextAfter,
+		})
+	}
+
+	return hunks, nil
+}
+
+func stringSlice(raw interface{}) ([]string, error) {
+	if raw == nil {
+		return nil, nil
+	}
+	rawList, ok := raw.([]interface{})
+	if !ok {
+		return nil, errors.New("must be an array of strings")
+	}
+	out := make([]string, len(rawList))
+	for i, v := range rawList {
+		s, ok := v.(string)
+		if !ok {
+			return nil, errors.New("must be an array of strings")
+		}
+		out[i] = s
+	}
+	return out, nil
+}
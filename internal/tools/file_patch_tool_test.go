@@ -0,0 +1,112 @@
+package tools
+
+import (
+	"context"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFilePatchToolExactMatch(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	assert.NoError(t, afero.WriteFile(fs, "/workspace/test.txt", []byte("line1\nline2\nline3\n"), 0644))
+
+	tool := NewFilePatchToolWithFS(fs)
+	result, err := tool.Execute(context.Background(), map[string]interface{}{
+		"file_path": "/workspace/test.txt",
+		"hunks": []interface{}{
+			map[string]interface{}{
+				"old_lines": []interface{}{"line2"},
+				"new_lines": []interface{}{"LINE2", "extra"},
+			},
+		},
+	})
+	assert.NoError(t, err)
+	output, ok := result.(*FilePatchToolOutput)
+	assert.True(t, ok)
+	assert.True(t, output.Success)
+	assert.Equal(t, []int{0}, output.Applied)
+
+	content, err := afero.ReadFile(fs, "/workspace/test.txt")
+	assert.NoError(t, err)
+	assert.Equal(t, "line1\nLINE2\nextra\nline3\n", string(content))
+}
+
+func TestFilePatchToolFuzzyWhitespaceMatch(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	assert.NoError(t, afero.WriteFile(fs, "/workspace/test.txt", []byte("func f() {\n    return 1\n}\n"), 0644))
+
+	tool := NewFilePatchToolWithFS(fs)
+	result, err := tool.Execute(context.Background(), map[string]interface{}{
+		"file_path": "/workspace/test.txt",
+		"hunks": []interface{}{
+			map[string]interface{}{
+				// Slightly different indentation than what's on disk.
+				"old_lines": []interface{}{"  return 1"},
+				"new_lines": []interface{}{"    return 2"},
+			},
+		},
+	})
+	assert.NoError(t, err)
+	output := result.(*FilePatchToolOutput)
+	assert.True(t, output.Success)
+
+	content, err := afero.ReadFile(fs, "/workspace/test.txt")
+	assert.NoError(t, err)
+	assert.Equal(t, "func f() {\n    return 2\n}\n", string(content))
+}
+
+func TestFilePatchToolRejectsNoMatch(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	assert.NoError(t, afero.WriteFile(fs, "/workspace/test.txt", []byte("alpha\nbeta\ngamma\n"), 0644))
+
+	tool := NewFilePatchToolWithFS(fs)
+	result, err := tool.Execute(context.Background(), map[string]interface{}{
+		"file_path": "/workspace/test.txt",
+		"hunks": []interface{}{
+			map[string]interface{}{
+				"old_lines": []interface{}{"this text does not appear anywhere"},
+				"new_lines": []interface{}{"replacement"},
+			},
+		},
+	})
+	assert.NoError(t, err)
+	output := result.(*FilePatchToolOutput)
+	assert.False(t, output.Success)
+	assert.Len(t, output.Rejected, 1)
+	assert.Contains(t, output.Rejected[0].Reason, "no matching context found")
+
+	// File is untouched since the only hunk was rejected.
+	content, err := afero.ReadFile(fs, "/workspace/test.txt")
+	assert.NoError(t, err)
+	assert.Equal(t, "alpha\nbeta\ngamma\n", string(content))
+}
+
+func TestFilePatchToolDryRun(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	assert.NoError(t, afero.WriteFile(fs, "/workspace/test.txt", []byte("one\ntwo\nthree\n"), 0644))
+
+	tool := NewFilePatchToolWithFS(fs)
+	result, err := tool.Execute(context.Background(), map[string]interface{}{
+		"file_path": "/workspace/test.txt",
+		"dry_run":   true,
+		"hunks": []interface{}{
+			map[string]interface{}{
+				"old_lines": []interface{}{"two"},
+				"new_lines": []interface{}{"TWO"},
+			},
+		},
+	})
+	assert.NoError(t, err)
+	output := result.(*FilePatchToolOutput)
+	assert.True(t, output.Success)
+	assert.True(t, output.DryRun)
+	assert.Contains(t, output.Diff, "-two")
+	assert.Contains(t, output.Diff, "+TWO")
+
+	// Dry run must not touch the file on disk.
+	content, err := afero.ReadFile(fs, "/workspace/test.txt")
+	assert.NoError(t, err)
+	assert.Equal(t, "one\ntwo\nthree\n", string(content))
+}
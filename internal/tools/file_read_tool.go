@@ -1,18 +1,39 @@
 package tools
 
 import (
+	"bufio"
 	"context"
+	"encoding/base64"
+	"encoding/hex"
 	"errors"
-	"io/ioutil"
+	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 
+	"github.com/spf13/afero"
+
 	"github.com/cinience/aigo-kode/internal/core"
+	"github.com/cinience/aigo-kode/internal/tools/schema"
 )
 
 // FileReadTool implements the Tool interface for reading file contents
-type FileReadTool struct{}
+type FileReadTool struct {
+	fs afero.Fs
+}
+
+// FileReadToolInput is FileReadTool's declared input shape, driving both
+// Arguments() and ValidateInput via the schema package.
+type FileReadToolInput struct {
+	FilePath  string  `json:"file_path" jsonschema:"required,minLength=1,description=The path to the file to read"`
+	Offset    float64 `json:"offset,omitempty" jsonschema:"minimum=0,description=0-based line number to start reading from"`
+	Limit     float64 `json:"limit,omitempty" jsonschema:"minimum=1,description=Maximum number of lines to read"`
+	LineRange string  `json:"line_range,omitempty" jsonschema:"description=1-based inclusive line range 'start-end' such as 100-200; overrides offset/limit when set"`
+}
+
+var fileReadToolSchema = schema.MustGenerate(FileReadToolInput{})
 
 // Name returns the tool name
 func (t *FileReadTool) Name() string {
@@ -26,11 +47,29 @@ func (t *FileReadTool) Description() string {
 
 // FileReadToolOutput defines the output structure for FileReadTool
 type FileReadToolOutput struct {
-	Type    string `json:"type"`
+	Type string `json:"type"`
+	// Content is decoded UTF-8 text for Type "text", or base64-encoded raw
+	// bytes (of the sniffed preview only, not the whole file) for Type
+	// "binary".
 	Content string `json:"content,omitempty"`
-	Error   string `json:"error,omitempty"`
+	// Encoding is the charset Content was transcoded from, e.g. "utf-8" or
+	// "utf-16le". Empty for "image"/"binary" results; an "error" result
+	// carries it only when decoding from that charset failed.
+	Encoding string `json:"encoding,omitempty"`
+	// HexPreview is a hex dump of the first bytes of a "binary" result.
+	HexPreview string `json:"hex_preview,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
+
+// OutputSchema returns the JSON Schema for FileReadToolOutput
+func (t *FileReadTool) OutputSchema() string {
+	return `{"type":"object","properties":{"type":{"type":"string"},"content":{"type":"string"},"encoding":{"type":"string"},"hex_preview":{"type":"string"},"error":{"type":"string"}}}`
 }
 
+// hexPreviewLimit bounds how many of a binary file's sniffed bytes are
+// rendered as a hex preview, so the output stays readable.
+const hexPreviewLimit = 256
+
 // Execute executes the file read operation
 func (t *FileReadTool) Execute(ctx context.Context, input map[string]interface{}) (interface{}, error) {
 	// Extract file path
@@ -39,7 +78,6 @@ func (t *FileReadTool) Execute(ctx context.Context, input map[string]interface{}
 		return nil, errors.New("file_path is required and must be a string")
 	}
 
-	// Extract optional parameters
 	var offset, limit int
 	if offsetVal, ok := input["offset"].(float64); ok {
 		offset = int(offsetVal)
@@ -47,9 +85,17 @@ func (t *FileReadTool) Execute(ctx context.Context, input map[string]interface{}
 	if limitVal, ok := input["limit"].(float64); ok {
 		limit = int(limitVal)
 	}
+	if lineRange, ok := input["line_range"].(string); ok && lineRange != "" {
+		start, end, err := parseLineRange(lineRange)
+		if err != nil {
+			return &FileReadToolOutput{Type: "error", Error: err.Error()}, nil
+		}
+		offset = start - 1
+		limit = end - start + 1
+	}
 
 	// Check if file exists
-	if _, err := os.Stat(filePath); os.IsNotExist(err) {
+	if _, err := t.fs.Stat(filePath); os.IsNotExist(err) {
 		return &FileReadToolOutput{
 			Type:  "error",
 			Error: "File does not exist",
@@ -69,75 +115,142 @@ func (t *FileReadTool) Execute(ctx context.Context, input map[string]interface{}
 		}, nil
 	}
 
-	// Read file content
-	content, err := ioutil.ReadFile(filePath)
+	file, err := t.fs.Open(filePath)
 	if err != nil {
+		return &FileReadToolOutput{Type: "error", Error: err.Error()}, nil
+	}
+	defer file.Close()
+
+	sample := make([]byte, binarySniffLimit)
+	n, err := io.ReadFull(file, sample)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return &FileReadToolOutput{Type: "error", Error: err.Error()}, nil
+	}
+	sample = sample[:n]
+
+	enc := detectEncoding(sample)
+
+	// looksBinary's null-byte heuristic misfires on UTF-16 text, which is
+	// roughly half NUL bytes for ASCII content; only trust it once
+	// detectEncoding has ruled out UTF-16.
+	if enc != encodingUTF16LE && enc != encodingUTF16BE && looksBinary(sample) {
+		preview := sample
+		if len(preview) > hexPreviewLimit {
+			preview = preview[:hexPreviewLimit]
+		}
 		return &FileReadToolOutput{
-			Type:  "error",
-			Error: err.Error(),
+			Type:       "binary",
+			Content:    base64.StdEncoding.EncodeToString(sample),
+			HexPreview: hex.EncodeToString(preview),
 		}, nil
 	}
 
-	// Convert to string
-	contentStr := string(content)
-	lines := strings.Split(contentStr, "\n")
-
-	// Apply offset and limit if provided
-	if offset > 0 && offset < len(lines) {
-		lines = lines[offset:]
+	// A bounded read (offset/limit/line_range) is paged line-by-line
+	// below; an unbounded read decodes the whole file up front here,
+	// same as before this file gained encoding support, so the returned
+	// Content round-trips the file's bytes exactly (including any trailing
+	// newline).
+	if offset == 0 && limit == 0 {
+		rest, err := io.ReadAll(file)
+		if err != nil {
+			return &FileReadToolOutput{Type: "error", Error: err.Error()}, nil
+		}
+		decoded, err := decodeToUTF8(append(append([]byte(nil), sample...), rest...), enc)
+		if err != nil {
+			return &FileReadToolOutput{Type: "error", Error: err.Error(), Encoding: string(enc)}, nil
+		}
+		return &FileReadToolOutput{
+			Type:     "text",
+			Content:  decoded,
+			Encoding: string(enc),
+		}, nil
 	}
-	if limit > 0 && limit < len(lines) {
-		lines = lines[:limit]
+
+	// Plain UTF-8 is scanned line-by-line without loading the whole file,
+	// so a large log can be paged without exhausting memory. Other
+	// encodings fall back to decoding the whole file up front, since their
+	// line breaks don't line up with single scan bytes the way UTF-8's do.
+	var lines []string
+	if enc == encodingUTF8 {
+		reader := io.MultiReader(strings.NewReader(string(stripBOM(sample))), file)
+		lines, err = scanLines(reader, offset, limit)
+		if err != nil {
+			return &FileReadToolOutput{Type: "error", Error: err.Error()}, nil
+		}
+	} else {
+		rest, err := io.ReadAll(file)
+		if err != nil {
+			return &FileReadToolOutput{Type: "error", Error: err.Error()}, nil
+		}
+		decoded, err := decodeToUTF8(append(sample, rest...), enc)
+		if err != nil {
+			return &FileReadToolOutput{Type: "error", Error: err.Error(), Encoding: string(enc)}, nil
+		}
+		allLines := strings.Split(decoded, "\n")
+		if offset > 0 && offset < len(allLines) {
+			allLines = allLines[offset:]
+		}
+		if limit > 0 && limit < len(allLines) {
+			allLines = allLines[:limit]
+		}
+		lines = allLines
 	}
 
 	return &FileReadToolOutput{
-		Type:    "text",
-		Content: strings.Join(lines, "\n"),
+		Type:     "text",
+		Content:  strings.Join(lines, "\n"),
+		Encoding: string(enc),
 	}, nil
 }
 
-// ValidateInput validates the input parameters
-func (t *FileReadTool) ValidateInput(input map[string]interface{}) error {
-	// Check if file_path exists and is a string
-	filePathVal, ok := input["file_path"]
-	if !ok {
-		return errors.New("file_path is required")
-	}
+// scanLines reads r line by line, skipping offset lines and collecting up
+// to limit lines (0 meaning unbounded), without holding more than the
+// requested window in memory.
+func scanLines(r io.Reader, offset, limit int) ([]string, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
 
-	filePath, ok := filePathVal.(string)
-	if !ok {
-		return errors.New("file_path must be a string")
+	var lines []string
+	lineNo := 0
+	for scanner.Scan() {
+		if lineNo >= offset {
+			if limit > 0 && len(lines) >= limit {
+				break
+			}
+			lines = append(lines, scanner.Text())
+		}
+		lineNo++
 	}
-
-	if filePath == "" {
-		return errors.New("file_path cannot be empty")
+	if err := scanner.Err(); err != nil {
+		return nil, err
 	}
+	return lines, nil
+}
 
-	// Validate offset if present
-	if offsetVal, ok := input["offset"]; ok {
-		offset, ok := offsetVal.(float64)
-		if !ok {
-			return errors.New("offset must be a number")
-		}
-
-		if offset < 0 {
-			return errors.New("offset must be non-negative")
-		}
+// parseLineRange parses a "start-end" 1-based inclusive line range.
+func parseLineRange(s string) (start, end int, err error) {
+	parts := strings.SplitN(s, "-", 2)
+	if len(parts) != 2 {
+		return 0, 0, fmt.Errorf("line_range must be 'start-end', got %q", s)
 	}
-
-	// Validate limit if present
-	if limitVal, ok := input["limit"]; ok {
-		limit, ok := limitVal.(float64)
-		if !ok {
-			return errors.New("limit must be a number")
-		}
-
-		if limit <= 0 {
-			return errors.New("limit must be positive")
-		}
+	start, err1 := strconv.Atoi(strings.TrimSpace(parts[0]))
+	end, err2 := strconv.Atoi(strings.TrimSpace(parts[1]))
+	if err1 != nil || err2 != nil || start < 1 || end < start {
+		return 0, 0, fmt.Errorf("line_range must be 'start-end' with 1 <= start <= end, got %q", s)
 	}
+	return start, end, nil
+}
 
-	return nil
+// ValidateInput validates the input parameters against FileReadToolInput's
+// generated schema
+func (t *FileReadTool) ValidateInput(input map[string]interface{}) error {
+	return fileReadToolSchema.Validate(input)
+}
+
+// Arguments returns the JSON Schema properties generated from
+// FileReadToolInput
+func (t *FileReadTool) Arguments() string {
+	return fileReadToolSchema.PropertiesJSON()
 }
 
 // IsReadOnly returns whether the tool is read-only
@@ -150,7 +263,15 @@ func (t *FileReadTool) RequiresPermission(input map[string]interface{}) bool {
 	return true
 }
 
-// NewFileReadTool creates a new FileReadTool
+// NewFileReadTool creates a new FileReadTool backed by the real OS
+// filesystem.
 func NewFileReadTool() core.Tool {
-	return &FileReadTool{}
+	return NewFileReadToolWithFS(afero.NewOsFs())
+}
+
+// NewFileReadToolWithFS creates a FileReadTool backed by fs, so a caller
+// can swap in an in-memory filesystem for tests, a chroot/basepath fs to
+// sandbox reads under a workspace root, or any other afero.Fs backend.
+func NewFileReadToolWithFS(fs afero.Fs) core.Tool {
+	return &FileReadTool{fs: fs}
 }
@@ -0,0 +1,145 @@
+package tools
+
+import (
+	"context"
+	"errors"
+
+	"github.com/spf13/afero"
+
+	"github.com/cinience/aigo-kode/internal/core"
+)
+
+// FileRevertTool implements the Tool interface for undoing edits that
+// FileEditTool or FilePatchTool recorded, by transaction id or by count.
+type FileRevertTool struct {
+	fs afero.Fs
+}
+
+// Name returns the tool name
+func (t *FileRevertTool) Name() string {
+	return "FileRevert"
+}
+
+// Description returns the tool description
+func (t *FileRevertTool) Description() string {
+	return "Reverts file edits previously made by FileEdit or FilePatch, undoing the most recent transaction (or a specified one) up to a given count"
+}
+
+// FileRevertToolOutput defines the output structure for FileRevertTool
+type FileRevertToolOutput struct {
+	Reverted []string `json:"reverted,omitempty"`
+	Error    string   `json:"error,omitempty"`
+}
+
+// OutputSchema returns the JSON Schema for FileRevertToolOutput
+func (t *FileRevertTool) OutputSchema() string {
+	return `{"type":"object","properties":{"reverted":{"type":"array","items":{"type":"string"}},"error":{"type":"string"}}}`
+}
+
+// Execute executes the file revert operation
+func (t *FileRevertTool) Execute(ctx context.Context, input map[string]interface{}) (interface{}, error) {
+	entries, err := readHistoryIndex(t.fs)
+	if err != nil {
+		return &FileRevertToolOutput{Error: "Failed to read history: " + err.Error()}, nil
+	}
+	if len(entries) == 0 {
+		return &FileRevertToolOutput{Error: "No recorded edits to revert"}, nil
+	}
+
+	transactionID, _ := input["transaction_id"].(string)
+	if transactionID == "" {
+		transactionID = lastTransactionID(entries)
+	}
+
+	count := 1
+	if countVal, ok := input["count"].(float64); ok && countVal > 0 {
+		count = int(countVal)
+	}
+
+	// Walk the index newest-first, pulling out up to count entries from
+	// the target transaction; everything else is kept in the index.
+	var toRevert []historyEntry
+	var remaining []historyEntry
+	for i := len(entries) - 1; i >= 0; i-- {
+		entry := entries[i]
+		if entry.TransactionID == transactionID && len(toRevert) < count {
+			toRevert = append(toRevert, entry)
+			continue
+		}
+		remaining = append([]historyEntry{entry}, remaining...)
+	}
+
+	if len(toRevert) == 0 {
+		return &FileRevertToolOutput{Error: "No matching edits to revert"}, nil
+	}
+
+	var reverted []string
+	for _, entry := range toRevert {
+		content, err := afero.ReadFile(t.fs, entry.BackupPath)
+		if err != nil {
+			return &FileRevertToolOutput{Reverted: reverted, Error: "Failed to read backup: " + err.Error()}, nil
+		}
+		if err := atomicWriteFile(t.fs, entry.FilePath, content, 0644); err != nil {
+			return &FileRevertToolOutput{Reverted: reverted, Error: "Failed to restore file: " + err.Error()}, nil
+		}
+		reverted = append(reverted, entry.FilePath)
+	}
+
+	if err := writeHistoryIndex(t.fs, remaining); err != nil {
+		return &FileRevertToolOutput{Reverted: reverted, Error: "Failed to update history: " + err.Error()}, nil
+	}
+
+	return &FileRevertToolOutput{Reverted: reverted}, nil
+}
+
+// ValidateInput validates the input parameters
+func (t *FileRevertTool) ValidateInput(input map[string]interface{}) error {
+	if transactionIDVal, ok := input["transaction_id"]; ok {
+		if _, ok := transactionIDVal.(string); !ok {
+			return errors.New("transaction_id must be a string")
+		}
+	}
+	if countVal, ok := input["count"]; ok {
+		if _, ok := countVal.(float64); !ok {
+			return errors.New("count must be a number")
+		}
+	}
+	return nil
+}
+
+func (t *FileRevertTool) Arguments() string {
+	return `{
+		"transaction_id": {
+			"type": "string",
+			"description": "The transaction to revert (default: the most recent transaction recorded by FileEdit or FilePatch)"
+		},
+		"count": {
+			"type": "number",
+			"description": "Maximum number of edits within the transaction to revert (default: 1)"
+		}
+	}`
+}
+
+// IsReadOnly returns whether the tool is read-only
+func (t *FileRevertTool) IsReadOnly() bool {
+	return false
+}
+
+// RequiresPermission checks if permission is needed
+func (t *FileRevertTool) RequiresPermission(input map[string]interface{}) bool {
+	return true
+}
+
+// NewFileRevertTool creates a new FileRevertTool backed by the real OS
+// filesystem.
+func NewFileRevertTool() core.Tool {
+	return NewFileRevertToolWithFS(afero.NewOsFs())
+}
+
+// NewFileRevertToolWithFS creates a FileRevertTool backed by fs, so a
+// caller can swap in an in-memory filesystem for tests, a chroot/basepath
+// fs to sandbox reverts under a workspace root, or any other afero.Fs
+// backend.
+func NewFileRevertToolWithFS(fs afero.Fs) core.Tool {
+	return &FileRevertTool{fs: fs}
+}
@@ -5,6 +5,7 @@ import (
 	"os"
 	"testing"
 
+	"github.com/spf13/afero"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -114,3 +115,115 @@ func TestFileWriteTool(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, "Test content\nAppended content", string(content))
 }
+
+func TestFileToolsWithInMemoryFS(t *testing.T) {
+	fs := afero.NewMemMapFs()
+
+	writeTool := NewFileWriteToolWithFS(fs)
+	result, err := writeTool.Execute(context.Background(), map[string]interface{}{
+		"file_path": "/workspace/test.txt",
+		"content":   "hello",
+	})
+	assert.NoError(t, err)
+	assert.True(t, result.(*FileWriteToolOutput).Success)
+
+	readTool := NewFileReadToolWithFS(fs)
+	result, err = readTool.Execute(context.Background(), map[string]interface{}{
+		"file_path": "/workspace/test.txt",
+	})
+	assert.NoError(t, err)
+	readResult := result.(*FileReadToolOutput)
+	assert.Equal(t, "text", readResult.Type)
+	assert.Equal(t, "hello\n", readResult.Content)
+
+	editTool := NewFileEditToolWithFS(fs)
+	result, err = editTool.Execute(context.Background(), map[string]interface{}{
+		"file_path": "/workspace/test.txt",
+		"old_text":  "hello",
+		"new_text":  "goodbye",
+	})
+	assert.NoError(t, err)
+	assert.True(t, result.(*FileEditToolOutput).Success)
+
+	content, err := afero.ReadFile(fs, "/workspace/test.txt")
+	assert.NoError(t, err)
+	assert.Equal(t, "goodbye\n", string(content))
+}
+
+func TestFileEditToolRevert(t *testing.T) {
+	fs := afero.NewMemMapFs()
+
+	err := afero.WriteFile(fs, "/workspace/test.txt", []byte("hello\n"), 0644)
+	assert.NoError(t, err)
+
+	editTool := NewFileEditToolWithFS(fs)
+	result, err := editTool.Execute(context.Background(), map[string]interface{}{
+		"file_path": "/workspace/test.txt",
+		"old_text":  "hello",
+		"new_text":  "goodbye",
+	})
+	assert.NoError(t, err)
+	assert.True(t, result.(*FileEditToolOutput).Success)
+
+	content, err := afero.ReadFile(fs, "/workspace/test.txt")
+	assert.NoError(t, err)
+	assert.Equal(t, "goodbye\n", string(content))
+
+	revertTool := NewFileRevertToolWithFS(fs)
+	result, err = revertTool.Execute(context.Background(), map[string]interface{}{})
+	assert.NoError(t, err)
+	revertResult, ok := result.(*FileRevertToolOutput)
+	assert.True(t, ok)
+	assert.Empty(t, revertResult.Error)
+	assert.Equal(t, []string{"/workspace/test.txt"}, revertResult.Reverted)
+
+	content, err = afero.ReadFile(fs, "/workspace/test.txt")
+	assert.NoError(t, err)
+	assert.Equal(t, "hello\n", string(content))
+
+	// A second revert has nothing left to undo.
+	result, err = revertTool.Execute(context.Background(), map[string]interface{}{})
+	assert.NoError(t, err)
+	assert.NotEmpty(t, result.(*FileRevertToolOutput).Error)
+}
+
+func TestFileEditToolTransactionGrouping(t *testing.T) {
+	fs := afero.NewMemMapFs()
+
+	assert.NoError(t, afero.WriteFile(fs, "/workspace/a.txt", []byte("a\n"), 0644))
+	assert.NoError(t, afero.WriteFile(fs, "/workspace/b.txt", []byte("b\n"), 0644))
+
+	editTool := NewFileEditToolWithFS(fs)
+	for _, f := range []struct{ path, old, new string }{
+		{"/workspace/a.txt", "a", "A"},
+		{"/workspace/b.txt", "b", "B"},
+	} {
+		result, err := editTool.Execute(context.Background(), map[string]interface{}{
+			"file_path":      f.path,
+			"old_text":       f.old,
+			"new_text":       f.new,
+			"transaction_id": "txn-shared",
+		})
+		assert.NoError(t, err)
+		assert.True(t, result.(*FileEditToolOutput).Success)
+	}
+
+	revertTool := NewFileRevertToolWithFS(fs)
+	result, err := revertTool.Execute(context.Background(), map[string]interface{}{
+		"transaction_id": "txn-shared",
+		"count":          float64(2),
+	})
+	assert.NoError(t, err)
+	revertResult, ok := result.(*FileRevertToolOutput)
+	assert.True(t, ok)
+	assert.Empty(t, revertResult.Error)
+	assert.ElementsMatch(t, []string{"/workspace/a.txt", "/workspace/b.txt"}, revertResult.Reverted)
+
+	contentA, err := afero.ReadFile(fs, "/workspace/a.txt")
+	assert.NoError(t, err)
+	assert.Equal(t, "a\n", string(contentA))
+
+	contentB, err := afero.ReadFile(fs, "/workspace/b.txt")
+	assert.NoError(t, err)
+	assert.Equal(t, "b\n", string(contentB))
+}
@@ -3,14 +3,21 @@ package tools
 import (
 	"context"
 	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/spf13/afero"
 
 	"github.com/cinience/aigo-kode/internal/core"
 )
 
 // FileWriteTool implements the Tool interface for writing to files
-type FileWriteTool struct{}
+type FileWriteTool struct {
+	fs afero.Fs
+}
 
 // Name returns the tool name
 func (t *FileWriteTool) Name() string {
@@ -26,6 +33,10 @@ func (t *FileWriteTool) Description() string {
 type FileWriteToolOutput struct {
 	Success bool   `json:"success"`
 	Error   string `json:"error,omitempty"`
+	// PermissionDenied is set when Error was caused specifically by the
+	// OS denying the write (os.IsPermission), distinct from e.g. a missing
+	// directory or a full disk.
+	PermissionDenied bool `json:"permission_denied,omitempty"`
 }
 
 // Execute executes the file write operation
@@ -58,12 +69,24 @@ func (t *FileWriteTool) Execute(ctx context.Context, input map[string]interface{
 		trailingNewline = trailingNewlineVal
 	}
 
+	enc := encodingUTF8
+	if encVal, ok := input["encoding"].(string); ok && encVal != "" {
+		enc = textEncoding(encVal)
+	}
+
+	lineEndings := "lf"
+	if lineEndingsVal, ok := input["line_endings"].(string); ok && lineEndingsVal != "" {
+		lineEndings = lineEndingsVal
+	}
+
 	// Ensure directory exists
 	dir := filepath.Dir(filePath)
-	if err := os.MkdirAll(dir, 0755); err != nil {
+	if err := t.fs.MkdirAll(dir, 0755); err != nil {
+		message, permissionDenied := classifyWriteError(err)
 		return &FileWriteToolOutput{
-			Success: false,
-			Error:   "Failed to create directory: " + err.Error(),
+			Success:          false,
+			Error:            "Failed to create directory: " + message,
+			PermissionDenied: permissionDenied,
 		}, nil
 	}
 
@@ -75,24 +98,35 @@ func (t *FileWriteTool) Execute(ctx context.Context, input map[string]interface{
 		content = content + "\n"
 	}
 
+	content = convertLineEndings(content, lineEndings)
+
+	data, encErr := encodeFromUTF8(content, enc)
+	if encErr != nil {
+		return &FileWriteToolOutput{Success: false, Error: encErr.Error()}, nil
+	}
+
 	var err error
 	if append {
 		// Open file in append mode
-		var file *os.File
-		file, err = os.OpenFile(filePath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+		var file afero.File
+		file, err = t.fs.OpenFile(filePath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
 		if err == nil {
-			_, err = file.WriteString(content)
+			_, err = file.Write(data)
 			file.Close()
 		}
 	} else {
-		// Write or overwrite file
-		err = os.WriteFile(filePath, []byte(content), 0644)
+		// Write or overwrite file atomically: a crash or context
+		// cancellation mid-write leaves the original file intact instead
+		// of a half-written truncation.
+		err = atomicWriteFile(t.fs, filePath, data, 0644)
 	}
 
 	if err != nil {
+		message, permissionDenied := classifyWriteError(err)
 		return &FileWriteToolOutput{
-			Success: false,
-			Error:   err.Error(),
+			Success:          false,
+			Error:            message,
+			PermissionDenied: permissionDenied,
 		}, nil
 	}
 
@@ -101,6 +135,25 @@ func (t *FileWriteTool) Execute(ctx context.Context, input map[string]interface{
 	}, nil
 }
 
+// convertLineEndings rewrites content's line endings to match mode ("lf",
+// "crlf", or "native" - "\r\n" on Windows, "\n" elsewhere). Content is
+// normalized to bare "\n" first, so mixed input line endings don't produce
+// doubled "\r" sequences.
+func convertLineEndings(content, mode string) string {
+	normalized := strings.ReplaceAll(content, "\r\n", "\n")
+	switch mode {
+	case "crlf":
+		return strings.ReplaceAll(normalized, "\n", "\r\n")
+	case "native":
+		if runtime.GOOS == "windows" {
+			return strings.ReplaceAll(normalized, "\n", "\r\n")
+		}
+		return normalized
+	default:
+		return normalized
+	}
+}
+
 // ValidateInput validates the input parameters
 func (t *FileWriteTool) ValidateInput(input map[string]interface{}) error {
 	// Check if file_path exists and is a string
@@ -153,6 +206,32 @@ func (t *FileWriteTool) ValidateInput(input map[string]interface{}) error {
 		}
 	}
 
+	// Validate encoding if present
+	if encodingVal, ok := input["encoding"]; ok {
+		encStr, ok := encodingVal.(string)
+		if !ok {
+			return errors.New("encoding must be a string")
+		}
+		switch textEncoding(encStr) {
+		case encodingUTF8, encodingUTF16LE, encodingUTF16BE, encodingLatin1:
+		default:
+			return fmt.Errorf("encoding %q is not supported for writing", encStr)
+		}
+	}
+
+	// Validate line_endings if present
+	if lineEndingsVal, ok := input["line_endings"]; ok {
+		lineEndingsStr, ok := lineEndingsVal.(string)
+		if !ok {
+			return errors.New("line_endings must be a string")
+		}
+		switch lineEndingsStr {
+		case "lf", "crlf", "native":
+		default:
+			return fmt.Errorf("line_endings must be 'lf', 'crlf', or 'native', got %q", lineEndingsStr)
+		}
+	}
+
 	return nil
 }
 
@@ -177,10 +256,22 @@ func (t *FileWriteTool) Arguments() string {
 		"trailing_newline": {
 			"type": "boolean",
 			"description": "Whether to add a trailing newline to the content (default: true)"
+		},
+		"encoding": {
+			"type": "string",
+			"description": "Charset to encode the written bytes as: utf-8 (default), utf-16le, utf-16be, or latin1"
+		},
+		"line_endings": {
+			"type": "string",
+			"description": "Line ending style to write: lf (default), crlf, or native (crlf on Windows, lf elsewhere)"
 		}
 	}`
 }
 
+// OutputSchema returns the JSON Schema for FileWriteToolOutput
+func (t *FileWriteTool) OutputSchema() string {
+	return `{"type":"object","properties":{"success":{"type":"boolean"},"error":{"type":"string"}}}`
+}
 
 // IsReadOnly returns whether the tool is read-only
 func (t *FileWriteTool) IsReadOnly() bool {
@@ -192,7 +283,22 @@ func (t *FileWriteTool) RequiresPermission(input map[string]interface{}) bool {
 	return true
 }
 
-// NewFileWriteTool creates a new FileWriteTool
+// Fingerprint narrows permission approvals to the directory being written
+// to, so approving a write doesn't blanket-approve writes anywhere else.
+func (t *FileWriteTool) Fingerprint(input map[string]interface{}) string {
+	filePath, _ := input["file_path"].(string)
+	return filepath.Dir(filePath)
+}
+
+// NewFileWriteTool creates a new FileWriteTool backed by the real OS
+// filesystem.
 func NewFileWriteTool() core.Tool {
-	return &FileWriteTool{}
+	return NewFileWriteToolWithFS(afero.NewOsFs())
+}
+
+// NewFileWriteToolWithFS creates a FileWriteTool backed by fs, so a caller
+// can swap in an in-memory filesystem for tests, a chroot/basepath fs to
+// sandbox writes under a workspace root, or any other afero.Fs backend.
+func NewFileWriteToolWithFS(fs afero.Fs) core.Tool {
+	return &FileWriteTool{fs: fs}
 }
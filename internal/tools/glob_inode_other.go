@@ -0,0 +1,12 @@
+//go:build !unix
+
+package tools
+
+import "os"
+
+// inodeKeyOf reports ok=false on platforms without a stable inode we can
+// read from os.FileInfo; symlink cycle detection there falls back to
+// relying on max_results/context cancellation to bound the walk.
+func inodeKeyOf(info os.FileInfo) (inodeKey, bool) {
+	return inodeKey{}, false
+}
@@ -0,0 +1,18 @@
+//go:build unix
+
+package tools
+
+import (
+	"os"
+	"syscall"
+)
+
+// inodeKeyOf extracts the device/inode pair identifying info's underlying
+// file, used to detect symlink cycles during a followed-symlinks walk.
+func inodeKeyOf(info os.FileInfo) (inodeKey, bool) {
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return inodeKey{}, false
+	}
+	return inodeKey{dev: uint64(stat.Dev), ino: stat.Ino}, true
+}
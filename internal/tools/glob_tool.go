@@ -3,14 +3,26 @@ package tools
 import (
 	"context"
 	"errors"
+	"io/fs"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 
 	"github.com/cinience/aigo-kode/internal/core"
+	"github.com/cinience/aigo-kode/internal/tools/ignore"
 )
 
-// GlobTool implements the Tool interface for finding files matching a pattern
+// defaultGlobMaxResults caps how many matches GlobTool returns when the
+// caller doesn't specify max_results.
+const defaultGlobMaxResults = 1000
+
+// defaultIgnoredDirs are pruned from every Glob walk regardless of whether a
+// .gitignore mentions them, since they're almost never useful to search.
+var defaultIgnoredDirs = []string{"node_modules/", "vendor/", ".git/"}
+
+// GlobTool implements the Tool interface for finding files matching a
+// pattern, with real "**" (any-depth) semantics and gitignore-aware pruning.
 type GlobTool struct{}
 
 // Name returns the tool name
@@ -20,92 +32,295 @@ func (t *GlobTool) Name() string {
 
 // Description returns the tool description
 func (t *GlobTool) Description() string {
-	return "Finds files matching a pattern"
+	return "Finds files matching a glob pattern (supports ** and {a,b} expansion), honoring .gitignore"
 }
 
 // GlobToolOutput defines the output structure for GlobTool
 type GlobToolOutput struct {
-	Files []string `json:"files"`
-	Error string   `json:"error,omitempty"`
+	Files   []string `json:"files"`
+	Omitted int      `json:"omitted,omitempty"`
+	Error   string   `json:"error,omitempty"`
 }
 
 // Execute executes the glob operation
 func (t *GlobTool) Execute(ctx context.Context, input map[string]interface{}) (interface{}, error) {
-	// Extract pattern
 	pattern, ok := input["pattern"].(string)
 	if !ok || pattern == "" {
 		return nil, errors.New("pattern is required and must be a string")
 	}
 
-	// Extract base directory (optional)
 	baseDir := "."
-	if baseDirVal, ok := input["base_dir"].(string); ok && baseDirVal != "" {
-		baseDir = baseDirVal
+	if v, ok := input["base_dir"].(string); ok && v != "" {
+		baseDir = v
 	}
-
-	// Ensure base directory exists
 	if _, err := os.Stat(baseDir); os.IsNotExist(err) {
-		return &GlobToolOutput{
-			Files: []string{},
-			Error: "Base directory does not exist",
-		}, nil
+		return &GlobToolOutput{Files: []string{}, Error: "Base directory does not exist"}, nil
 	}
 
-	// If pattern doesn't contain a path separator, search in all subdirectories
-	if !strings.Contains(pattern, string(filepath.Separator)) {
-		pattern = filepath.Join(baseDir, "**", pattern)
-	} else if !filepath.IsAbs(pattern) {
-		// If pattern is relative, make it relative to baseDir
-		pattern = filepath.Join(baseDir, pattern)
+	// A pattern with no path separator should match at any depth, not just
+	// directly inside base_dir.
+	if !strings.Contains(pattern, "/") {
+		pattern = "**/" + pattern
 	}
 
-	// Find files matching pattern
-	matches, err := filepath.Glob(pattern)
+	include, err := grepStringSlice(input["include"])
 	if err != nil {
-		return &GlobToolOutput{
-			Files: []string{},
-			Error: "Invalid pattern: " + err.Error(),
-		}, nil
+		return nil, err
+	}
+	exclude, err := grepStringSlice(input["exclude"])
+	if err != nil {
+		return nil, err
+	}
+
+	followSymlinks := false
+	if v, ok := input["follow_symlinks"].(bool); ok {
+		followSymlinks = v
+	}
+
+	maxResults, _ := grepIntParam(input, "max_results", defaultGlobMaxResults)
+	if maxResults <= 0 {
+		maxResults = defaultGlobMaxResults
+	}
+
+	walker := &globWalker{
+		baseDir:        baseDir,
+		pattern:        pattern,
+		include:        include,
+		exclude:        exclude,
+		matcher:        globIgnoreMatcher(baseDir),
+		followSymlinks: followSymlinks,
+		visitedInodes:  make(map[inodeKey]bool),
+		maxResults:     maxResults,
+	}
+	if err := walker.walk(ctx); err != nil {
+		return &GlobToolOutput{Files: []string{}, Error: err.Error()}, nil
+	}
+
+	sort.Strings(walker.matches)
+	return &GlobToolOutput{Files: walker.matches, Omitted: walker.omitted}, nil
+}
+
+// globIgnoreMatcher builds the ignore.Matcher used while walking baseDir: it
+// starts from the repo's own .gitignore/.ignore, layers on a project-local
+// .kodeignore, a global ~/.go-anon-kode/ignore, and always prunes the
+// noisiest well-known directories.
+func globIgnoreMatcher(baseDir string) *ignore.Matcher {
+	matcher := ignore.New()
+
+	for _, p := range defaultIgnoredDirs {
+		matcher.AddPattern(".", p)
+	}
+
+	for _, name := range []string{".gitignore", ".ignore", filepath.Join(".git", "info", "exclude"), ".kodeignore"} {
+		path := filepath.Join(baseDir, name)
+		if fileExists(path) {
+			_ = matcher.LoadFile(baseDir, path)
+		}
+	}
+
+	if homeDir, err := os.UserHomeDir(); err == nil {
+		globalIgnore := filepath.Join(homeDir, ".go-anon-kode", "ignore")
+		if fileExists(globalIgnore) {
+			_ = matcher.LoadFile(baseDir, globalIgnore)
+		}
+	}
+
+	return matcher
+}
+
+// globWalker carries the state needed to walk a tree once, applying the
+// pattern/include/exclude filters and enforcing max_results.
+type globWalker struct {
+	baseDir        string
+	pattern        string
+	include        []string
+	exclude        []string
+	matcher        *ignore.Matcher
+	followSymlinks bool
+	visitedInodes  map[inodeKey]bool
+	maxResults     int
+
+	matches []string
+	omitted int
+}
+
+func (w *globWalker) walk(ctx context.Context) error {
+	return filepath.WalkDir(w.baseDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+		if path == w.baseDir {
+			return nil
+		}
+
+		rel, relErr := filepath.Rel(w.baseDir, path)
+		if relErr != nil {
+			return nil
+		}
+		rel = filepath.ToSlash(rel)
+
+		isDir := d.IsDir()
+		if d.Type()&fs.ModeSymlink != 0 {
+			if !w.followSymlinks {
+				return nil
+			}
+			info, statErr := os.Stat(path)
+			if statErr != nil {
+				return nil
+			}
+			isDir = info.IsDir()
+			if isDir && w.seenBefore(info) {
+				return filepath.SkipDir
+			}
+		}
+
+		if isDir {
+			for _, name := range []string{".gitignore", ".ignore"} {
+				if ignoreFile := filepath.Join(path, name); fileExists(ignoreFile) {
+					_ = w.matcher.LoadFile(w.baseDir, ignoreFile)
+				}
+			}
+			if w.matcher.Match(rel, true) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if w.matcher.Match(rel, false) {
+			return nil
+		}
+		if !globMatch(w.pattern, rel) {
+			return nil
+		}
+		if len(w.include) > 0 && !globMatchAny(w.include, rel) {
+			return nil
+		}
+		if globMatchAny(w.exclude, rel) {
+			return nil
+		}
+
+		if len(w.matches) >= w.maxResults {
+			w.omitted++
+			return nil
+		}
+		w.matches = append(w.matches, path)
+		return nil
+	})
+}
+
+// inodeKey identifies a file by device+inode so symlink cycles can be
+// detected even when the same target is reached through different paths.
+type inodeKey struct {
+	dev, ino uint64
+}
+
+// seenBefore records info's device/inode and reports whether it had already
+// been visited, so a symlink cycle doesn't send the walk into a loop.
+func (w *globWalker) seenBefore(info os.FileInfo) bool {
+	key, ok := inodeKeyOf(info)
+	if !ok {
+		return false
 	}
+	if w.visitedInodes[key] {
+		return true
+	}
+	w.visitedInodes[key] = true
+	return false
+}
 
-	// Filter out directories
-	files := make([]string, 0, len(matches))
-	for _, match := range matches {
-		info, err := os.Stat(match)
-		if err == nil && !info.IsDir() {
-			files = append(files, match)
+// globMatchAny reports whether rel matches any of the given glob patterns.
+func globMatchAny(patterns []string, rel string) bool {
+	for _, p := range patterns {
+		if globMatch(p, rel) {
+			return true
 		}
 	}
+	return false
+}
 
-	return &GlobToolOutput{
-		Files: files,
-	}, nil
+// globMatch reports whether path matches a doublestar glob pattern,
+// expanding any {a,b} brace groups in pattern first.
+func globMatch(pattern, path string) bool {
+	for _, p := range expandBraces(pattern) {
+		if doubleStarMatch(strings.Split(p, "/"), strings.Split(path, "/")) {
+			return true
+		}
+	}
+	return false
+}
+
+// expandBraces expands the first {a,b,...} group in pattern into all of its
+// alternatives, recursively, so "*.{go,md}" becomes ["*.go", "*.md"].
+func expandBraces(pattern string) []string {
+	start := strings.IndexByte(pattern, '{')
+	if start == -1 {
+		return []string{pattern}
+	}
+	end := strings.IndexByte(pattern[start:], '}')
+	if end == -1 {
+		return []string{pattern}
+	}
+	end += start
+
+	prefix, suffix := pattern[:start], pattern[end+1:]
+	var out []string
+	for _, opt := range strings.Split(pattern[start+1:end], ",") {
+		out = append(out, expandBraces(prefix+opt+suffix)...)
+	}
+	return out
+}
+
+// doubleStarMatch matches pattern segments against path segments, treating
+// a "**" segment as "zero or more directories" and every other segment as a
+// filepath.Match pattern (so *, ?, and [...] classes work per-segment).
+func doubleStarMatch(pattern, path []string) bool {
+	if len(pattern) == 0 {
+		return len(path) == 0
+	}
+
+	if pattern[0] == "**" {
+		if len(pattern) == 1 {
+			return true
+		}
+		for i := 0; i <= len(path); i++ {
+			if doubleStarMatch(pattern[1:], path[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+
+	if len(path) == 0 {
+		return false
+	}
+	ok, err := filepath.Match(pattern[0], path[0])
+	if err != nil || !ok {
+		return false
+	}
+	return doubleStarMatch(pattern[1:], path[1:])
 }
 
 // ValidateInput validates the input parameters
 func (t *GlobTool) ValidateInput(input map[string]interface{}) error {
-	// Check if pattern exists and is a string
 	patternVal, ok := input["pattern"]
 	if !ok {
 		return errors.New("pattern is required")
 	}
-
 	pattern, ok := patternVal.(string)
-	if !ok {
-		return errors.New("pattern must be a string")
-	}
-
-	if pattern == "" {
-		return errors.New("pattern cannot be empty")
+	if !ok || pattern == "" {
+		return errors.New("pattern must be a non-empty string")
 	}
 
-	// Validate base_dir if present
 	if baseDirVal, ok := input["base_dir"]; ok {
 		baseDir, ok := baseDirVal.(string)
 		if !ok {
 			return errors.New("base_dir must be a string")
 		}
-
 		if baseDir != "" {
 			if _, err := os.Stat(baseDir); os.IsNotExist(err) {
 				return errors.New("base_dir does not exist")
@@ -113,9 +328,64 @@ func (t *GlobTool) ValidateInput(input map[string]interface{}) error {
 		}
 	}
 
+	for _, key := range []string{"include", "exclude"} {
+		if _, err := grepStringSlice(input[key]); err != nil {
+			return err
+		}
+	}
+
+	if v, ok := input["follow_symlinks"]; ok {
+		if _, ok := v.(bool); !ok {
+			return errors.New("follow_symlinks must be a boolean")
+		}
+	}
+
+	if v, ok := input["max_results"]; ok {
+		if _, ok := v.(float64); !ok {
+			return errors.New("max_results must be a number")
+		}
+	}
+
 	return nil
 }
 
+// Arguments returns the JSON schema fragment describing GlobTool's input.
+func (t *GlobTool) Arguments() string {
+	return `{
+		"pattern": {
+			"type": "string",
+			"description": "Glob pattern; supports **, *, ?, [...], and {a,b} brace expansion"
+		},
+		"base_dir": {
+			"type": "string",
+			"description": "Directory to search from (default: current directory)"
+		},
+		"include": {
+			"type": "array",
+			"items": {"type": "string"},
+			"description": "Additional patterns a match must also satisfy"
+		},
+		"exclude": {
+			"type": "array",
+			"items": {"type": "string"},
+			"description": "Patterns that exclude an otherwise-matching file"
+		},
+		"follow_symlinks": {
+			"type": "boolean",
+			"description": "Whether to follow symlinked directories (default: false)"
+		},
+		"max_results": {
+			"type": "number",
+			"description": "Maximum number of files to return (default 1000)"
+		}
+	}`
+}
+
+// OutputSchema returns the JSON Schema for GlobToolOutput
+func (t *GlobTool) OutputSchema() string {
+	return `{"type":"object","properties":{"files":{"type":"array","items":{"type":"string"}},"omitted":{"type":"integer"},"error":{"type":"string"}}}`
+}
+
 // IsReadOnly returns whether the tool is read-only
 func (t *GlobTool) IsReadOnly() bool {
 	return true
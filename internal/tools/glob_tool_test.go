@@ -0,0 +1,102 @@
+package tools
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGlobTool(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "globtest")
+	if err != nil {
+		t.Fatalf("Failed to create temp directory: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	testFiles := []string{"test1.txt", "test2.txt", "test.go", "subdir/test3.txt"}
+	for _, file := range testFiles {
+		filePath := filepath.Join(tmpDir, file)
+		os.MkdirAll(filepath.Dir(filePath), 0755)
+		os.WriteFile(filePath, []byte("test content"), 0644)
+	}
+	os.MkdirAll(filepath.Join(tmpDir, "vendor"), 0755)
+	os.WriteFile(filepath.Join(tmpDir, "vendor", "test4.txt"), []byte("test content"), 0644)
+
+	tool := NewGlobTool()
+
+	err = tool.ValidateInput(map[string]interface{}{
+		"pattern":  "*.txt",
+		"base_dir": tmpDir,
+	})
+	assert.NoError(t, err)
+
+	err = tool.ValidateInput(map[string]interface{}{"base_dir": tmpDir})
+	assert.Error(t, err)
+
+	// A bare pattern with no "/" should match at any depth, and vendor/
+	// should be pruned by default even without a .gitignore.
+	result, err := tool.Execute(context.Background(), map[string]interface{}{
+		"pattern":  "*.txt",
+		"base_dir": tmpDir,
+	})
+	assert.NoError(t, err)
+	globResult, ok := result.(*GlobToolOutput)
+	assert.True(t, ok)
+	assert.Len(t, globResult.Files, 3) // test1.txt, test2.txt, subdir/test3.txt
+
+	// An explicit "**/" pattern should behave the same way.
+	result, err = tool.Execute(context.Background(), map[string]interface{}{
+		"pattern":  "**/test1.txt",
+		"base_dir": tmpDir,
+	})
+	assert.NoError(t, err)
+	globResult = result.(*GlobToolOutput)
+	assert.Len(t, globResult.Files, 1)
+
+	// Brace expansion.
+	result, err = tool.Execute(context.Background(), map[string]interface{}{
+		"pattern":  "*.{go,txt}",
+		"base_dir": tmpDir,
+		"exclude":  []interface{}{"subdir/**"},
+	})
+	assert.NoError(t, err)
+	globResult = result.(*GlobToolOutput)
+	assert.Len(t, globResult.Files, 3) // test1.txt, test2.txt, test.go
+
+	// max_results caps the result set and reports how many were omitted.
+	result, err = tool.Execute(context.Background(), map[string]interface{}{
+		"pattern":     "*.txt",
+		"base_dir":    tmpDir,
+		"max_results": float64(1),
+	})
+	assert.NoError(t, err)
+	globResult = result.(*GlobToolOutput)
+	assert.Len(t, globResult.Files, 1)
+	assert.Equal(t, 2, globResult.Omitted)
+}
+
+func TestGlobToolGitignore(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "globignoretest")
+	if err != nil {
+		t.Fatalf("Failed to create temp directory: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	os.WriteFile(filepath.Join(tmpDir, "keep.go"), []byte("package main"), 0644)
+	os.MkdirAll(filepath.Join(tmpDir, "build"), 0755)
+	os.WriteFile(filepath.Join(tmpDir, "build", "gen.go"), []byte("package main"), 0644)
+	os.WriteFile(filepath.Join(tmpDir, ".gitignore"), []byte("build/\n"), 0644)
+
+	tool := NewGlobTool()
+	result, err := tool.Execute(context.Background(), map[string]interface{}{
+		"pattern":  "*.go",
+		"base_dir": tmpDir,
+	})
+	assert.NoError(t, err)
+	globResult := result.(*GlobToolOutput)
+	assert.Len(t, globResult.Files, 1)
+	assert.Contains(t, globResult.Files[0], "keep.go")
+}
@@ -1,16 +1,35 @@
 package tools
 
 import (
+	"bufio"
+	"bytes"
 	"context"
 	"errors"
+	"io/fs"
 	"os"
 	"path/filepath"
-	"strings"
+	"regexp"
+	"runtime"
+	"sort"
+	"sync"
+	"sync/atomic"
 
 	"github.com/cinience/aigo-kode/internal/core"
+	"github.com/cinience/aigo-kode/internal/tools/ignore"
 )
 
-// GrepTool implements the Tool interface for searching file contents
+// defaultGrepMaxMatches caps the total number of matches returned when the
+// caller doesn't specify max_matches, so a broad pattern over a huge tree
+// can't produce an unbounded response.
+const defaultGrepMaxMatches = 1000
+
+// defaultGrepMaxLineLength bounds how long a single line can be before
+// bufio.Scanner gives up on it, protecting against minified/binary-ish
+// files with no newlines.
+const defaultGrepMaxLineLength = 1 << 20 // 1 MiB
+
+// GrepTool implements the Tool interface for searching file contents with a
+// regular expression, walking directory trees while honoring .gitignore.
 type GrepTool struct{}
 
 // Name returns the tool name
@@ -20,161 +39,412 @@ func (t *GrepTool) Name() string {
 
 // Description returns the tool description
 func (t *GrepTool) Description() string {
-	return "Searches for text patterns in files"
+	return "Searches file contents for a regular expression pattern, honoring .gitignore"
 }
 
-// GrepToolOutput defines the output structure for GrepTool
+// GrepMatch is a single matching line within a file, optionally with
+// surrounding context lines.
 type GrepMatch struct {
-	File    string `json:"file"`
-	Line    int    `json:"line"`
-	Content string `json:"content"`
+	Line   int      `json:"line"`
+	Col    int      `json:"col"`
+	Match  string   `json:"match"`
+	Before []string `json:"before,omitempty"`
+	After  []string `json:"after,omitempty"`
 }
 
-type GrepToolOutput struct {
+// GrepFileResult groups every match found within a single file.
+type GrepFileResult struct {
+	File    string      `json:"file"`
 	Matches []GrepMatch `json:"matches"`
-	Error   string      `json:"error,omitempty"`
+}
+
+// GrepToolOutput defines the output structure for GrepTool
+type GrepToolOutput struct {
+	Results   []GrepFileResult `json:"results"`
+	Truncated bool             `json:"truncated,omitempty"`
+	Error     string           `json:"error,omitempty"`
 }
 
 // Execute executes the grep operation
 func (t *GrepTool) Execute(ctx context.Context, input map[string]interface{}) (interface{}, error) {
-	// Extract pattern
 	pattern, ok := input["pattern"].(string)
 	if !ok || pattern == "" {
 		return nil, errors.New("pattern is required and must be a string")
 	}
 
-	// Extract file paths or patterns
-	var filePaths []string
-	if filePathsVal, ok := input["file_paths"].([]interface{}); ok {
-		for _, pathVal := range filePathsVal {
-			if path, ok := pathVal.(string); ok && path != "" {
-				filePaths = append(filePaths, path)
+	paths, err := grepStringSlice(input["paths"])
+	if err != nil {
+		return nil, err
+	}
+	if len(paths) == 0 {
+		return nil, errors.New("paths is required and must be a string or array of strings")
+	}
+
+	re, err := compileGrepPattern(pattern, input)
+	if err != nil {
+		return &GrepToolOutput{Error: "invalid pattern: " + err.Error()}, nil
+	}
+
+	before, _ := grepIntParam(input, "before_context", 0)
+	after, _ := grepIntParam(input, "after_context", 0)
+	maxMatches, _ := grepIntParam(input, "max_matches", defaultGrepMaxMatches)
+	if maxMatches <= 0 {
+		maxMatches = defaultGrepMaxMatches
+	}
+
+	files := grepCollectFiles(paths)
+
+	var (
+		remaining = int64(maxMatches)
+		mu        sync.Mutex
+		results   = make(map[string][]GrepMatch)
+		wg        sync.WaitGroup
+	)
+
+	workerCount := runtime.GOMAXPROCS(0)
+	if workerCount < 1 {
+		workerCount = 1
+	}
+	fileCh := make(chan string)
+
+	for i := 0; i < workerCount; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for file := range fileCh {
+				if atomic.LoadInt64(&remaining) <= 0 {
+					continue
+				}
+				matches := grepFile(file, re, before, after, &remaining)
+				if len(matches) == 0 {
+					continue
+				}
+				mu.Lock()
+				results[file] = matches
+				mu.Unlock()
 			}
+		}()
+	}
+
+dispatch:
+	for _, file := range files {
+		select {
+		case <-ctx.Done():
+			break dispatch
+		case fileCh <- file:
+		}
+		if atomic.LoadInt64(&remaining) <= 0 {
+			break
 		}
-	} else if filePathVal, ok := input["file_paths"].(string); ok && filePathVal != "" {
-		filePaths = []string{filePathVal}
 	}
+	close(fileCh)
+	wg.Wait()
+
+	fileNames := make([]string, 0, len(results))
+	for file := range results {
+		fileNames = append(fileNames, file)
+	}
+	sort.Strings(fileNames)
+
+	output := &GrepToolOutput{Results: make([]GrepFileResult, 0, len(fileNames))}
+	for _, file := range fileNames {
+		output.Results = append(output.Results, GrepFileResult{File: file, Matches: results[file]})
+	}
+	if atomic.LoadInt64(&remaining) <= 0 {
+		output.Truncated = true
+	}
+
+	return output, nil
+}
+
+// compileGrepPattern builds a *regexp.Regexp honoring the case_insensitive,
+// whole_word, and multiline flags.
+func compileGrepPattern(pattern string, input map[string]interface{}) (*regexp.Regexp, error) {
+	if caseInsensitive, ok := input["case_insensitive"].(bool); ok && caseInsensitive {
+		pattern = "(?i)" + pattern
+	}
+	if multiline, ok := input["multiline"].(bool); ok && multiline {
+		pattern = "(?m)" + pattern
+	}
+	if wholeWord, ok := input["whole_word"].(bool); ok && wholeWord {
+		pattern = `\b(?:` + pattern + `)\b`
+	}
+	return regexp.Compile(pattern)
+}
+
+// grepFile streams a single file line-by-line, returning every matching
+// line (with context) while decrementing remaining atomically so the
+// global max_matches cap is enforced across all worker goroutines.
+func grepFile(path string, re *regexp.Regexp, before, after int, remaining *int64) []GrepMatch {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
 
-	if len(filePaths) == 0 {
-		return nil, errors.New("file_paths is required and must be a string or array of strings")
+	if isBinaryFile(f) {
+		return nil
 	}
 
-	// Extract max matches (optional)
-	maxMatches := 100
-	if maxMatchesVal, ok := input["max_matches"].(float64); ok {
-		maxMatches = int(maxMatchesVal)
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), defaultGrepMaxLineLength)
+
+	var (
+		lineNum    int
+		beforeRing = make([]string, 0, before)
+		pending    []*GrepMatch // matches still accepting "after" lines
+		matches    []GrepMatch
+	)
+
+	flush := func(m *GrepMatch) {
+		matches = append(matches, *m)
 	}
 
-	// Process each file path
-	var allMatches []GrepMatch
-	for _, path := range filePaths {
-		// Handle glob patterns
-		matches, err := filepath.Glob(path)
-		if err != nil || len(matches) == 0 {
-			// If not a glob pattern or no matches, treat as a single file
-			matches = []string{path}
+	for scanner.Scan() {
+		lineNum++
+		line := scanner.Text()
+
+		// Feed any pending matches their "after" context.
+		still := pending[:0]
+		for _, m := range pending {
+			m.After = append(m.After, line)
+			if len(m.After) >= after {
+				flush(m)
+			} else {
+				still = append(still, m)
+			}
+		}
+		pending = still
+
+		if loc := re.FindStringIndex(line); loc != nil {
+			if atomic.AddInt64(remaining, -1) < 0 {
+				atomic.AddInt64(remaining, 1) // give the slot back, we didn't use it
+				break
+			}
+			m := &GrepMatch{
+				Line:   lineNum,
+				Col:    loc[0] + 1,
+				Match:  line[loc[0]:loc[1]],
+				Before: append([]string(nil), beforeRing...),
+			}
+			if after == 0 {
+				flush(m)
+			} else {
+				pending = append(pending, m)
+			}
 		}
 
-		// Process each matched file
-		for _, filePath := range matches {
-			// Check if file exists and is a regular file
-			info, err := os.Stat(filePath)
-			if err != nil || info.IsDir() {
-				continue
+		if before > 0 {
+			beforeRing = append(beforeRing, line)
+			if len(beforeRing) > before {
+				beforeRing = beforeRing[len(beforeRing)-before:]
 			}
+		}
+	}
+	for _, m := range pending {
+		flush(m)
+	}
+
+	return matches
+}
+
+// isBinaryFile reports whether f looks like a binary file by checking the
+// first 8 KiB for a NUL byte, then rewinds the file so the caller can still
+// scan it from the start.
+func isBinaryFile(f *os.File) bool {
+	buf := make([]byte, 8192)
+	n, _ := f.Read(buf)
+	_, _ = f.Seek(0, 0)
+	return bytes.IndexByte(buf[:n], 0) != -1
+}
+
+// grepCollectFiles walks every root in paths, returning the list of regular
+// files to search, honoring nested .gitignore/.ignore/.git/info/exclude
+// files along the way.
+func grepCollectFiles(paths []string) []string {
+	var files []string
+
+	for _, root := range paths {
+		info, err := os.Stat(root)
+		if err != nil {
+			continue
+		}
+		if !info.IsDir() {
+			files = append(files, root)
+			continue
+		}
 
-			// Read file content
-			content, err := os.ReadFile(filePath)
+		matcher := ignore.New()
+		loadAncestorIgnoreFiles(matcher, root)
+
+		_ = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
 			if err != nil {
-				continue
+				return nil
+			}
+			if path == root {
+				return nil
+			}
+
+			rel, relErr := filepath.Rel(root, path)
+			if relErr != nil {
+				return nil
 			}
 
-			// Search for pattern in each line
-			lines := strings.Split(string(content), "\n")
-			for i, line := range lines {
-				if strings.Contains(line, pattern) {
-					allMatches = append(allMatches, GrepMatch{
-						File:    filePath,
-						Line:    i + 1,
-						Content: line,
-					})
-
-					// Check if we've reached the maximum number of matches
-					if len(allMatches) >= maxMatches {
-						break
+			if d.IsDir() {
+				for _, name := range []string{".gitignore", ".ignore"} {
+					if ignoreFile := filepath.Join(path, name); fileExists(ignoreFile) {
+						_ = matcher.LoadFile(root, ignoreFile)
 					}
 				}
+				if matcher.Match(rel, true) || d.Name() == ".git" {
+					return filepath.SkipDir
+				}
+				return nil
 			}
 
-			// Check if we've reached the maximum number of matches
-			if len(allMatches) >= maxMatches {
-				break
+			if matcher.Match(rel, false) {
+				return nil
 			}
+			files = append(files, path)
+			return nil
+		})
+	}
+
+	return files
+}
+
+// loadAncestorIgnoreFiles loads .gitignore/.ignore/.git/info/exclude files
+// from root itself before the walk begins, so top-level rules are in effect
+// immediately.
+func loadAncestorIgnoreFiles(matcher *ignore.Matcher, root string) {
+	for _, rel := range []string{".gitignore", ".ignore", filepath.Join(".git", "info", "exclude")} {
+		path := filepath.Join(root, rel)
+		if fileExists(path) {
+			_ = matcher.LoadFile(root, path)
 		}
+	}
+}
 
-		// Check if we've reached the maximum number of matches
-		if len(allMatches) >= maxMatches {
-			break
+func fileExists(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && !info.IsDir()
+}
+
+// grepStringSlice normalizes a paths-like input value (string or
+// []interface{} of strings) into a []string.
+func grepStringSlice(v interface{}) ([]string, error) {
+	switch val := v.(type) {
+	case nil:
+		return nil, nil
+	case string:
+		if val == "" {
+			return nil, nil
 		}
+		return []string{val}, nil
+	case []interface{}:
+		out := make([]string, 0, len(val))
+		for _, item := range val {
+			s, ok := item.(string)
+			if !ok {
+				return nil, errors.New("paths entries must be strings")
+			}
+			out = append(out, s)
+		}
+		return out, nil
+	default:
+		return nil, errors.New("paths must be a string or array of strings")
 	}
+}
 
-	return &GrepToolOutput{
-		Matches: allMatches,
-	}, nil
+// grepIntParam reads an integer-ish parameter (JSON numbers decode as
+// float64) from input, returning def if it's absent.
+func grepIntParam(input map[string]interface{}, key string, def int) (int, bool) {
+	v, ok := input[key]
+	if !ok {
+		return def, false
+	}
+	f, ok := v.(float64)
+	if !ok {
+		return def, false
+	}
+	return int(f), true
 }
 
 // ValidateInput validates the input parameters
 func (t *GrepTool) ValidateInput(input map[string]interface{}) error {
-	// Check if pattern exists and is a string
 	patternVal, ok := input["pattern"]
 	if !ok {
 		return errors.New("pattern is required")
 	}
-
 	pattern, ok := patternVal.(string)
-	if !ok {
-		return errors.New("pattern must be a string")
+	if !ok || pattern == "" {
+		return errors.New("pattern must be a non-empty string")
 	}
-
-	if pattern == "" {
-		return errors.New("pattern cannot be empty")
+	if _, err := compileGrepPattern(pattern, input); err != nil {
+		return errors.New("invalid pattern: " + err.Error())
 	}
 
-	// Check if file_paths exists and is a string or array of strings
-	filePathsVal, ok := input["file_paths"]
-	if !ok {
-		return errors.New("file_paths is required")
+	paths, err := grepStringSlice(input["paths"])
+	if err != nil {
+		return err
+	}
+	if len(paths) == 0 {
+		return errors.New("paths is required and must be a non-empty string or array of strings")
 	}
 
-	// Check if file_paths is a string
-	if _, ok := filePathsVal.(string); !ok {
-		// If not a string, check if it's an array
-		filePathsArray, ok := filePathsVal.([]interface{})
-		if !ok || len(filePathsArray) == 0 {
-			return errors.New("file_paths must be a non-empty string or array of strings")
-		}
-
-		// Check that all elements in the array are strings
-		for _, pathVal := range filePathsArray {
-			if _, ok := pathVal.(string); !ok {
-				return errors.New("all elements in file_paths array must be strings")
+	for _, key := range []string{"before_context", "after_context", "max_matches"} {
+		if v, ok := input[key]; ok {
+			if _, ok := v.(float64); !ok {
+				return errors.New(key + " must be a number")
 			}
 		}
 	}
 
-	// Validate max_matches if present
-	if maxMatchesVal, ok := input["max_matches"]; ok {
-		maxMatches, ok := maxMatchesVal.(float64)
-		if !ok {
-			return errors.New("max_matches must be a number")
-		}
+	return nil
+}
 
-		if maxMatches <= 0 {
-			return errors.New("max_matches must be positive")
+// Arguments returns the JSON schema fragment describing GrepTool's input.
+func (t *GrepTool) Arguments() string {
+	return `{
+		"pattern": {
+			"type": "string",
+			"description": "RE2 regular expression to search for"
+		},
+		"paths": {
+			"type": "array",
+			"items": {"type": "string"},
+			"description": "File or directory roots to search"
+		},
+		"case_insensitive": {
+			"type": "boolean",
+			"description": "Match case-insensitively"
+		},
+		"whole_word": {
+			"type": "boolean",
+			"description": "Only match whole words"
+		},
+		"multiline": {
+			"type": "boolean",
+			"description": "Enable multiline (?m) matching of ^/$"
+		},
+		"before_context": {
+			"type": "number",
+			"description": "Number of lines of context to include before each match"
+		},
+		"after_context": {
+			"type": "number",
+			"description": "Number of lines of context to include after each match"
+		},
+		"max_matches": {
+			"type": "number",
+			"description": "Maximum total matches to return across all files (default 1000)"
 		}
-	}
+	}`
+}
 
-	return nil
+// OutputSchema returns the JSON Schema for GrepToolOutput
+func (t *GrepTool) OutputSchema() string {
+	return `{"type":"object","properties":{"results":{"type":"array","items":{"type":"object","properties":{"file":{"type":"string"},"matches":{"type":"array","items":{"type":"object","properties":{"line":{"type":"integer"},"col":{"type":"integer"},"match":{"type":"string"},"before":{"type":"array","items":{"type":"string"}},"after":{"type":"array","items":{"type":"string"}}}}}}}},"truncated":{"type":"boolean"},"error":{"type":"string"}}}`
 }
 
 // IsReadOnly returns whether the tool is read-only
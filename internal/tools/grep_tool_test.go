@@ -0,0 +1,76 @@
+package tools
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGrepTool(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "greptest")
+	if err != nil {
+		t.Fatalf("Failed to create temp directory: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	os.WriteFile(filepath.Join(tmpDir, "a.go"), []byte("package main\nfunc Foo() {}\n"), 0644)
+	os.WriteFile(filepath.Join(tmpDir, "b.go"), []byte("package main\nfunc Bar() {}\n// Foo reference\n"), 0644)
+	os.MkdirAll(filepath.Join(tmpDir, "vendor"), 0755)
+	os.WriteFile(filepath.Join(tmpDir, "vendor", "c.go"), []byte("func Foo() {}\n"), 0644)
+	os.WriteFile(filepath.Join(tmpDir, ".gitignore"), []byte("vendor/\n"), 0644)
+
+	tool := NewGrepTool()
+
+	err = tool.ValidateInput(map[string]interface{}{
+		"pattern": "Foo",
+		"paths":   []interface{}{tmpDir},
+	})
+	assert.NoError(t, err)
+
+	err = tool.ValidateInput(map[string]interface{}{"paths": []interface{}{tmpDir}})
+	assert.Error(t, err)
+
+	err = tool.ValidateInput(map[string]interface{}{"pattern": "(", "paths": []interface{}{tmpDir}})
+	assert.Error(t, err)
+
+	result, err := tool.Execute(context.Background(), map[string]interface{}{
+		"pattern": "Foo",
+		"paths":   []interface{}{tmpDir},
+	})
+	assert.NoError(t, err)
+
+	output, ok := result.(*GrepToolOutput)
+	assert.True(t, ok)
+
+	// vendor/c.go should be pruned by .gitignore, leaving a.go and b.go.
+	assert.Len(t, output.Results, 2)
+	for _, r := range output.Results {
+		assert.NotContains(t, r.File, "vendor")
+	}
+
+	result, err = tool.Execute(context.Background(), map[string]interface{}{
+		"pattern":          "foo",
+		"paths":            []interface{}{tmpDir},
+		"case_insensitive": true,
+	})
+	assert.NoError(t, err)
+	output = result.(*GrepToolOutput)
+	assert.Len(t, output.Results, 2)
+
+	result, err = tool.Execute(context.Background(), map[string]interface{}{
+		"pattern":     "Foo",
+		"paths":       []interface{}{tmpDir},
+		"max_matches": float64(1),
+	})
+	assert.NoError(t, err)
+	output = result.(*GrepToolOutput)
+	totalMatches := 0
+	for _, r := range output.Results {
+		totalMatches += len(r.Matches)
+	}
+	assert.Equal(t, 1, totalMatches)
+	assert.True(t, output.Truncated)
+}
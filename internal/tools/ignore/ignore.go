@@ -0,0 +1,180 @@
+// Package ignore implements a small gitignore-style pattern matcher shared
+// by the tools that walk the filesystem (Grep, Glob) so they prune noisy
+// directories like node_modules, vendor, and .git the same way git itself
+// would.
+package ignore
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// rule is a single parsed ignore-file line, anchored to the directory the
+// file that defined it lives in.
+type rule struct {
+	base     string // directory the owning ignore file lives in, slash-separated
+	segments []string
+	negate   bool
+	dirOnly  bool
+	anchored bool // pattern contained a "/" before the last segment, or started with "/"
+}
+
+// Matcher evaluates a path against an ordered set of gitignore-style rules.
+// Rules are evaluated ancestor-first: a rule from a directory further up the
+// tree applies everywhere below it, but a more specific (deeper) rule, or a
+// later rule in the same file, overrides it - matching git's own precedence.
+type Matcher struct {
+	rules []rule
+}
+
+// New creates an empty Matcher. Use LoadFile/AddPattern to populate it.
+func New() *Matcher {
+	return &Matcher{}
+}
+
+// AddPattern adds a single gitignore-style pattern, anchored to base (a
+// slash-separated directory path relative to the matcher's root).
+func (m *Matcher) AddPattern(base, pattern string) {
+	if r, ok := parsePattern(base, pattern); ok {
+		m.rules = append(m.rules, r)
+	}
+}
+
+// LoadFile parses a gitignore-style file (one pattern per line, '#'
+// comments, blank lines skipped) and anchors every pattern to the file's
+// containing directory expressed relative to root.
+func (m *Matcher) LoadFile(root, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	base, err := filepath.Rel(root, filepath.Dir(path))
+	if err != nil {
+		base = "."
+	}
+	base = filepath.ToSlash(base)
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := scanner.Text()
+		trimmed := strings.TrimRight(line, " \t")
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		m.AddPattern(base, trimmed)
+	}
+	return scanner.Err()
+}
+
+// parsePattern turns a single gitignore line into a rule, or returns
+// ok=false for lines that don't produce one (currently none, kept for
+// symmetry with how git treats escaped blank patterns).
+func parsePattern(base, pattern string) (rule, bool) {
+	negate := strings.HasPrefix(pattern, "!")
+	if negate {
+		pattern = pattern[1:]
+	}
+
+	dirOnly := strings.HasSuffix(pattern, "/")
+	pattern = strings.TrimSuffix(pattern, "/")
+
+	anchored := strings.HasPrefix(pattern, "/")
+	pattern = strings.TrimPrefix(pattern, "/")
+	if strings.Contains(pattern, "/") {
+		anchored = true
+	}
+
+	return rule{
+		base:     base,
+		segments: strings.Split(pattern, "/"),
+		negate:   negate,
+		dirOnly:  dirOnly,
+		anchored: anchored,
+	}, true
+}
+
+// Match reports whether relPath (slash-separated, relative to the matcher's
+// root) should be ignored. isDir indicates whether relPath names a
+// directory, since dir-only ("trailing slash") rules only apply to those.
+func (m *Matcher) Match(relPath string, isDir bool) bool {
+	relPath = filepath.ToSlash(relPath)
+
+	ignored := false
+	for _, r := range m.rules {
+		if r.dirOnly && !isDir {
+			// A dir-only rule can still match a file under an ignored
+			// directory; that's handled by the caller pruning whole
+			// subtrees, so here we only match the directory entry itself.
+			continue
+		}
+		if r.matches(relPath) {
+			ignored = !r.negate
+		}
+	}
+	return ignored
+}
+
+// matches reports whether the rule applies to relPath.
+func (r rule) matches(relPath string) bool {
+	// Express relPath relative to the rule's own base directory.
+	candidate := relPath
+	if r.base != "." && r.base != "" {
+		prefix := r.base + "/"
+		if !strings.HasPrefix(relPath+"/", prefix) {
+			return false
+		}
+		candidate = strings.TrimPrefix(relPath, prefix)
+	}
+	if candidate == "" {
+		return false
+	}
+
+	if r.anchored {
+		return matchSegments(r.segments, strings.Split(candidate, "/"))
+	}
+
+	// Unanchored single-segment patterns (the common case, e.g. "*.log")
+	// may match at any depth, so try matching against every suffix of the
+	// path's segments.
+	parts := strings.Split(candidate, "/")
+	for i := range parts {
+		if matchSegments(r.segments, parts[i:]) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchSegments matches pattern segments (which may include a "**"
+// wildcard meaning "zero or more directories") against path segments.
+func matchSegments(pattern, path []string) bool {
+	if len(pattern) == 0 {
+		return len(path) == 0
+	}
+
+	if pattern[0] == "**" {
+		if len(pattern) == 1 {
+			return true
+		}
+		for i := 0; i <= len(path); i++ {
+			if matchSegments(pattern[1:], path[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+
+	if len(path) == 0 {
+		return false
+	}
+
+	ok, err := filepath.Match(pattern[0], path[0])
+	if err != nil || !ok {
+		return false
+	}
+	return matchSegments(pattern[1:], path[1:])
+}
@@ -0,0 +1,55 @@
+package ignore
+
+import "testing"
+
+func TestMatcherBasicPatterns(t *testing.T) {
+	m := New()
+	m.AddPattern(".", "*.log")
+	m.AddPattern(".", "/node_modules/")
+	m.AddPattern(".", "build")
+
+	cases := []struct {
+		path  string
+		isDir bool
+		want  bool
+	}{
+		{"debug.log", false, true},
+		{"src/debug.log", false, true},
+		{"node_modules", true, true},
+		{"src/node_modules", true, false}, // anchored to root
+		{"build", true, true},
+		{"src/build", true, true}, // unanchored, matches anywhere
+		{"main.go", false, false},
+	}
+
+	for _, c := range cases {
+		if got := m.Match(c.path, c.isDir); got != c.want {
+			t.Errorf("Match(%q, dir=%v) = %v, want %v", c.path, c.isDir, got, c.want)
+		}
+	}
+}
+
+func TestMatcherNegation(t *testing.T) {
+	m := New()
+	m.AddPattern(".", "*.log")
+	m.AddPattern(".", "!important.log")
+
+	if !m.Match("debug.log", false) {
+		t.Error("expected debug.log to be ignored")
+	}
+	if m.Match("important.log", false) {
+		t.Error("expected important.log to be un-ignored by negation")
+	}
+}
+
+func TestMatcherDoubleStar(t *testing.T) {
+	m := New()
+	m.AddPattern(".", "**/testdata/**")
+
+	if !m.Match("a/b/testdata/fixture.json", false) {
+		t.Error("expected nested testdata file to match **/testdata/**")
+	}
+	if m.Match("a/b/other/fixture.json", false) {
+		t.Error("did not expect unrelated path to match")
+	}
+}
@@ -3,15 +3,28 @@ package tools
 import (
 	"context"
 	"errors"
-	"io/ioutil"
+	"fmt"
+	"io/fs"
+	"mime"
 	"os"
 	"path/filepath"
 	"sort"
+	"strings"
+	"time"
 
 	"github.com/cinience/aigo-kode/internal/core"
 )
 
-// LSTool implements the Tool interface for listing directory contents
+// defaultLSLimit caps how many entries LSTool returns per page when the
+// caller doesn't specify limit.
+const defaultLSLimit = 1000
+
+// defaultLSMaxDepth bounds how many levels a recursive listing can descend,
+// regardless of the requested depth.
+const defaultLSMaxDepth = 20
+
+// LSTool implements the Tool interface for listing directory contents, with
+// pagination, sorting, and optional gitignore-aware recursive traversal.
 type LSTool struct{}
 
 // Name returns the tool name
@@ -21,33 +34,40 @@ func (t *LSTool) Name() string {
 
 // Description returns the tool description
 func (t *LSTool) Description() string {
-	return "Lists files and directories in a specified path"
+	return "Lists files and directories, with pagination, sorting, filtering, and recursive listing"
 }
 
 // LSEntry represents a file or directory entry
 type LSEntry struct {
-	Name      string `json:"name"`
-	Path      string `json:"path"`
-	IsDir     bool   `json:"is_dir"`
-	Size      int64  `json:"size"`
-	Extension string `json:"extension,omitempty"`
+	Name             string `json:"name"`
+	Path             string `json:"path"`
+	IsDir            bool   `json:"is_dir"`
+	Size             int64  `json:"size"`
+	SizeHuman        string `json:"size_human,omitempty"`
+	Extension        string `json:"extension,omitempty"`
+	ModTime          string `json:"mod_time"`
+	Mode             string `json:"mode"`
+	SymlinkTarget    string `json:"symlink_target,omitempty"`
+	MimeType         string `json:"mime_type,omitempty"`
+	NumChildrenIfDir int    `json:"num_children_if_dir,omitempty"`
 }
 
 // LSToolOutput defines the output structure for LSTool
 type LSToolOutput struct {
-	Entries []LSEntry `json:"entries"`
-	Error   string    `json:"error,omitempty"`
+	Entries    []LSEntry `json:"entries"`
+	Total      int       `json:"total"`
+	ParentPath string    `json:"parent_path,omitempty"`
+	CanGoUp    bool      `json:"can_go_up"`
+	Error      string    `json:"error,omitempty"`
 }
 
 // Execute executes the ls operation
 func (t *LSTool) Execute(ctx context.Context, input map[string]interface{}) (interface{}, error) {
-	// Extract directory path
 	dirPath, ok := input["path"].(string)
-	if !ok {
+	if !ok || dirPath == "" {
 		dirPath = "." // Default to current directory
 	}
 
-	// Check if directory exists
 	info, err := os.Stat(dirPath)
 	if err != nil {
 		return &LSToolOutput{
@@ -58,20 +78,48 @@ func (t *LSTool) Execute(ctx context.Context, input map[string]interface{}) (int
 
 	// If path is a file, return info about just that file
 	if !info.IsDir() {
-		entry := LSEntry{
-			Name:      filepath.Base(dirPath),
-			Path:      dirPath,
-			IsDir:     false,
-			Size:      info.Size(),
-			Extension: filepath.Ext(dirPath),
-		}
-		return &LSToolOutput{
-			Entries: []LSEntry{entry},
-		}, nil
+		entry := buildLSEntry(dirPath, info)
+		return &LSToolOutput{Entries: []LSEntry{entry}, Total: 1}, nil
+	}
+
+	showHidden := false
+	if v, ok := input["show_hidden"].(bool); ok {
+		showHidden = v
+	}
+
+	sortBy := "name"
+	if v, ok := input["sort_by"].(string); ok && v != "" {
+		sortBy = v
+	}
+
+	order := "asc"
+	if v, ok := input["order"].(string); ok && v != "" {
+		order = v
 	}
 
-	// Read directory contents
-	files, err := ioutil.ReadDir(dirPath)
+	filter := ""
+	if v, ok := input["filter"].(string); ok {
+		filter = v
+	}
+
+	depth, _ := grepIntParam(input, "depth", 0)
+	if depth < 0 {
+		depth = 0
+	}
+	if depth > defaultLSMaxDepth {
+		depth = defaultLSMaxDepth
+	}
+
+	limit, _ := grepIntParam(input, "limit", defaultLSLimit)
+	if limit <= 0 {
+		limit = defaultLSLimit
+	}
+	offset, _ := grepIntParam(input, "offset", 0)
+	if offset < 0 {
+		offset = 0
+	}
+
+	entries, err := collectLSEntries(dirPath, showHidden, filter, depth)
 	if err != nil {
 		return &LSToolOutput{
 			Entries: []LSEntry{},
@@ -79,64 +127,192 @@ func (t *LSTool) Execute(ctx context.Context, input map[string]interface{}) (int
 		}, nil
 	}
 
-	// Extract optional parameters
-	showHidden := false
-	if showHiddenVal, ok := input["show_hidden"].(bool); ok {
-		showHidden = showHiddenVal
+	sortLSEntries(entries, sortBy, order)
+
+	total := len(entries)
+	if offset > total {
+		offset = total
+	}
+	end := offset + limit
+	if end > total {
+		end = total
 	}
 
-	sortBy := "name"
-	if sortByVal, ok := input["sort_by"].(string); ok {
-		sortBy = sortByVal
+	absPath, err := filepath.Abs(dirPath)
+	if err != nil {
+		absPath = dirPath
+	}
+	parent := filepath.Dir(absPath)
+
+	return &LSToolOutput{
+		Entries:    entries[offset:end],
+		Total:      total,
+		ParentPath: parent,
+		CanGoUp:    parent != absPath,
+	}, nil
+}
+
+// collectLSEntries lists root's contents: a flat listing of direct children
+// when depth is 0, or a gitignore-aware recursive walk up to depth levels
+// otherwise.
+func collectLSEntries(root string, showHidden bool, filter string, depth int) ([]LSEntry, error) {
+	if depth == 0 {
+		return readLSDir(root, showHidden, filter)
+	}
+
+	matcher := globIgnoreMatcher(root)
+	var entries []LSEntry
+
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if path == root {
+			return nil
+		}
+
+		rel, relErr := filepath.Rel(root, path)
+		if relErr != nil {
+			return nil
+		}
+		rel = filepath.ToSlash(rel)
+
+		if !showHidden && strings.HasPrefix(d.Name(), ".") {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if d.IsDir() {
+			for _, name := range []string{".gitignore", ".ignore"} {
+				if ignoreFile := filepath.Join(path, name); fileExists(ignoreFile) {
+					_ = matcher.LoadFile(root, ignoreFile)
+				}
+			}
+			if matcher.Match(rel, true) {
+				return filepath.SkipDir
+			}
+		} else if matcher.Match(rel, false) {
+			return nil
+		}
+
+		if strings.Count(rel, "/") > depth {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if filter != "" && !globMatch(filter, rel) {
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return nil
+		}
+		entries = append(entries, buildLSEntry(path, info))
+		return nil
+	})
+
+	return entries, err
+}
+
+// readLSDir lists the direct, non-recursive contents of root.
+func readLSDir(root string, showHidden bool, filter string) ([]LSEntry, error) {
+	files, err := os.ReadDir(root)
+	if err != nil {
+		return nil, err
 	}
 
-	// Process directory entries
 	entries := make([]LSEntry, 0, len(files))
 	for _, file := range files {
-		// Skip hidden files if not showing them
-		if !showHidden && file.Name()[0] == '.' {
+		if !showHidden && strings.HasPrefix(file.Name(), ".") {
 			continue
 		}
+		if filter != "" && !globMatch(filter, file.Name()) {
+			continue
+		}
+		info, err := file.Info()
+		if err != nil {
+			continue
+		}
+		entries = append(entries, buildLSEntry(filepath.Join(root, file.Name()), info))
+	}
+	return entries, nil
+}
+
+// buildLSEntry turns an os.FileInfo into the rich LSEntry the API returns.
+func buildLSEntry(path string, info os.FileInfo) LSEntry {
+	entry := LSEntry{
+		Name:    info.Name(),
+		Path:    path,
+		IsDir:   info.IsDir(),
+		Size:    info.Size(),
+		ModTime: info.ModTime().UTC().Format(time.RFC3339),
+		Mode:    info.Mode().String(),
+	}
+
+	if !entry.IsDir {
+		entry.SizeHuman = humanizeSize(entry.Size)
+		entry.Extension = filepath.Ext(path)
+		entry.MimeType = mime.TypeByExtension(entry.Extension)
+	}
 
-		entry := LSEntry{
-			Name:  file.Name(),
-			Path:  filepath.Join(dirPath, file.Name()),
-			IsDir: file.IsDir(),
-			Size:  file.Size(),
+	if info.Mode()&os.ModeSymlink != 0 {
+		if target, err := os.Readlink(path); err == nil {
+			entry.SymlinkTarget = target
 		}
+	}
 
-		if !file.IsDir() {
-			entry.Extension = filepath.Ext(file.Name())
+	if entry.IsDir {
+		if children, err := os.ReadDir(path); err == nil {
+			entry.NumChildrenIfDir = len(children)
 		}
+	}
+
+	return entry
+}
 
-		entries = append(entries, entry)
+// humanizeSize renders size as a human-readable string (e.g. "1.5 MiB").
+func humanizeSize(size int64) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
 	}
+	div, exp := int64(unit), 0
+	for n := size / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(size)/float64(div), "KMGTPE"[exp])
+}
 
-	// Sort entries
-	switch sortBy {
-	case "name":
-		sort.Slice(entries, func(i, j int) bool {
-			return entries[i].Name < entries[j].Name
-		})
-	case "size":
-		sort.Slice(entries, func(i, j int) bool {
+// sortLSEntries sorts entries in place by sortBy (name|size|mtime|type),
+// in order (asc|desc).
+func sortLSEntries(entries []LSEntry, sortBy, order string) {
+	less := func(i, j int) bool {
+		switch sortBy {
+		case "size":
 			return entries[i].Size < entries[j].Size
-		})
-	case "type":
-		sort.Slice(entries, func(i, j int) bool {
-			if entries[i].IsDir && !entries[j].IsDir {
-				return true
-			}
-			if !entries[i].IsDir && entries[j].IsDir {
-				return false
+		case "mtime":
+			return entries[i].ModTime < entries[j].ModTime
+		case "type":
+			if entries[i].IsDir != entries[j].IsDir {
+				return entries[i].IsDir
 			}
 			return entries[i].Name < entries[j].Name
-		})
+		default:
+			return entries[i].Name < entries[j].Name
+		}
 	}
-
-	return &LSToolOutput{
-		Entries: entries,
-	}, nil
+	sort.SliceStable(entries, func(i, j int) bool {
+		if order == "desc" {
+			return less(j, i)
+		}
+		return less(i, j)
+	})
 }
 
 // ValidateInput validates the input parameters
@@ -155,50 +331,91 @@ func (t *LSTool) ValidateInput(input map[string]interface{}) error {
 		}
 	}
 
-	// Validate show_hidden if present
-	if showHiddenVal, ok := input["show_hidden"]; ok {
-		_, ok := showHiddenVal.(bool)
-		if !ok {
+	if v, ok := input["show_hidden"]; ok {
+		if _, ok := v.(bool); !ok {
 			return errors.New("show_hidden must be a boolean")
 		}
 	}
 
-	// Validate sort_by if present
-	if sortByVal, ok := input["sort_by"]; ok {
-		sortBy, ok := sortByVal.(string)
+	if v, ok := input["sort_by"]; ok {
+		sortBy, ok := v.(string)
 		if !ok {
 			return errors.New("sort_by must be a string")
 		}
+		validSortOptions := map[string]bool{"name": true, "size": true, "mtime": true, "type": true}
+		if !validSortOptions[sortBy] {
+			return errors.New("sort_by must be one of: name, size, mtime, type")
+		}
+	}
 
-		validSortOptions := map[string]bool{
-			"name": true,
-			"size": true,
-			"type": true,
+	if v, ok := input["order"]; ok {
+		order, ok := v.(string)
+		if !ok {
+			return errors.New("order must be a string")
+		}
+		if order != "asc" && order != "desc" {
+			return errors.New("order must be one of: asc, desc")
 		}
+	}
 
-		if !validSortOptions[sortBy] {
-			return errors.New("sort_by must be one of: name, size, type")
+	if v, ok := input["filter"]; ok {
+		if _, ok := v.(string); !ok {
+			return errors.New("filter must be a string")
+		}
+	}
+
+	for _, key := range []string{"depth", "limit", "offset"} {
+		if v, ok := input[key]; ok {
+			if _, ok := v.(float64); !ok {
+				return errors.New(key + " must be a number")
+			}
 		}
 	}
 
 	return nil
 }
 
+// Arguments returns the JSON schema fragment describing LSTool's input.
 func (t *LSTool) Arguments() string {
 	return `{
 		"path": {
 			"type": "string",
-			"description": "The path to the directory to list (optional)"
+			"description": "The directory (or file) to list (default: current directory)"
 		},
 		"show_hidden": {
 			"type": "boolean",
-			"description": "Whether to show hidden files and directories (optional)"
+			"description": "Whether to show hidden files and directories"
 		},
 		"sort_by": {
 			"type": "string",
-			"description": "The field to sort by (optional): name, size, type"
+			"description": "Field to sort by: name, size, mtime, or type (default: name)"
+		},
+		"order": {
+			"type": "string",
+			"description": "Sort order: asc or desc (default: asc)"
+		},
+		"filter": {
+			"type": "string",
+			"description": "Glob pattern entries must match"
+		},
+		"depth": {
+			"type": "number",
+			"description": "How many levels to recurse into subdirectories (default 0, flat listing)"
+		},
+		"limit": {
+			"type": "number",
+			"description": "Maximum number of entries to return (default 1000)"
+		},
+		"offset": {
+			"type": "number",
+			"description": "Number of entries to skip, for pagination"
 		}
-		`
+	}`
+}
+
+// OutputSchema returns the JSON Schema for LSToolOutput
+func (t *LSTool) OutputSchema() string {
+	return `{"type":"object","properties":{"entries":{"type":"array","items":{"type":"object","properties":{"name":{"type":"string"},"path":{"type":"string"},"is_dir":{"type":"boolean"},"size":{"type":"integer"},"size_human":{"type":"string"},"extension":{"type":"string"},"mod_time":{"type":"string"},"mode":{"type":"string"},"symlink_target":{"type":"string"},"mime_type":{"type":"string"},"num_children_if_dir":{"type":"integer"}}}},"total":{"type":"integer"},"parent_path":{"type":"string"},"can_go_up":{"type":"boolean"},"error":{"type":"string"}}}`
 }
 
 // IsReadOnly returns whether the tool is read-only
@@ -0,0 +1,93 @@
+package tools
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLSTool(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "lstest")
+	if err != nil {
+		t.Fatalf("Failed to create temp directory: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	os.WriteFile(filepath.Join(tmpDir, "a.txt"), []byte("small"), 0644)
+	os.WriteFile(filepath.Join(tmpDir, "b.txt"), []byte("a bit bigger file"), 0644)
+	os.MkdirAll(filepath.Join(tmpDir, "subdir"), 0755)
+	os.WriteFile(filepath.Join(tmpDir, "subdir", "c.txt"), []byte("nested"), 0644)
+
+	tool := NewLSTool()
+
+	err = tool.ValidateInput(map[string]interface{}{"path": tmpDir, "sort_by": "size"})
+	assert.NoError(t, err)
+
+	err = tool.ValidateInput(map[string]interface{}{"sort_by": "bogus"})
+	assert.Error(t, err)
+
+	// Flat listing (depth 0) should not descend into subdir.
+	result, err := tool.Execute(context.Background(), map[string]interface{}{"path": tmpDir})
+	assert.NoError(t, err)
+	output, ok := result.(*LSToolOutput)
+	assert.True(t, ok)
+	assert.Equal(t, 3, output.Total) // a.txt, b.txt, subdir
+	assert.True(t, output.CanGoUp)
+
+	// Sort by size descending should put b.txt first among files.
+	result, err = tool.Execute(context.Background(), map[string]interface{}{
+		"path":    tmpDir,
+		"sort_by": "size",
+		"order":   "desc",
+		"filter":  "*.txt",
+	})
+	assert.NoError(t, err)
+	output = result.(*LSToolOutput)
+	assert.Equal(t, 2, output.Total)
+	assert.Equal(t, "b.txt", output.Entries[0].Name)
+	assert.NotEmpty(t, output.Entries[0].SizeHuman)
+
+	// Pagination via limit/offset.
+	result, err = tool.Execute(context.Background(), map[string]interface{}{
+		"path":  tmpDir,
+		"limit": float64(1),
+	})
+	assert.NoError(t, err)
+	output = result.(*LSToolOutput)
+	assert.Len(t, output.Entries, 1)
+	assert.Equal(t, 3, output.Total)
+
+	// Recursive listing (depth > 0) should include the nested file.
+	result, err = tool.Execute(context.Background(), map[string]interface{}{
+		"path":  tmpDir,
+		"depth": float64(5),
+	})
+	assert.NoError(t, err)
+	output = result.(*LSToolOutput)
+	assert.Equal(t, 4, output.Total) // a.txt, b.txt, subdir, subdir/c.txt
+}
+
+func TestLSToolGitignore(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "lsignoretest")
+	if err != nil {
+		t.Fatalf("Failed to create temp directory: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	os.WriteFile(filepath.Join(tmpDir, "keep.go"), []byte("package main"), 0644)
+	os.MkdirAll(filepath.Join(tmpDir, "vendor"), 0755)
+	os.WriteFile(filepath.Join(tmpDir, "vendor", "gen.go"), []byte("package main"), 0644)
+
+	tool := NewLSTool()
+	result, err := tool.Execute(context.Background(), map[string]interface{}{
+		"path":  tmpDir,
+		"depth": float64(5),
+	})
+	assert.NoError(t, err)
+	output := result.(*LSToolOutput)
+	assert.Equal(t, 1, output.Total) // vendor/ is pruned by default
+	assert.Equal(t, "keep.go", output.Entries[0].Name)
+}
@@ -0,0 +1,172 @@
+package lsp
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// NotificationHandler is invoked for every notification the server sends
+// that the client isn't already waiting on as a request response, e.g.
+// textDocument/publishDiagnostics.
+type NotificationHandler func(method string, params json.RawMessage)
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *rpcError) Error() string {
+	return fmt.Sprintf("lsp: server returned error %d: %s", e.Code, e.Message)
+}
+
+type rpcEnvelope struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      *int64          `json:"id,omitempty"`
+	Method  string          `json:"method,omitempty"`
+	Params  json.RawMessage `json:"params,omitempty"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+}
+
+// client speaks JSON-RPC 2.0 over stdio to a single language server
+// process, using the LSP Content-Length header framing.
+type client struct {
+	w   io.Writer
+	wMu sync.Mutex
+
+	nextID  int64
+	pending sync.Map // int64 -> chan rpcEnvelope
+
+	onNotify NotificationHandler
+}
+
+// newClient starts a background goroutine reading LSP-framed messages from
+// r, and returns a client that writes requests/notifications to w.
+func newClient(w io.Writer, r io.Reader, onNotify NotificationHandler) *client {
+	c := &client{w: w, onNotify: onNotify}
+	go c.readLoop(bufio.NewReader(r))
+	return c
+}
+
+// Call sends a request and blocks until the matching response arrives.
+func (c *client) Call(method string, params interface{}, result interface{}) error {
+	id := atomic.AddInt64(&c.nextID, 1)
+	ch := make(chan rpcEnvelope, 1)
+	c.pending.Store(id, ch)
+	defer c.pending.Delete(id)
+
+	if err := c.send(rpcEnvelope{JSONRPC: "2.0", ID: &id, Method: method, Params: mustMarshal(params)}); err != nil {
+		return err
+	}
+
+	env := <-ch
+	if env.Error != nil {
+		return env.Error
+	}
+	if result == nil || len(env.Result) == 0 {
+		return nil
+	}
+	return json.Unmarshal(env.Result, result)
+}
+
+// Notify sends a one-way notification with no response expected.
+func (c *client) Notify(method string, params interface{}) error {
+	return c.send(rpcEnvelope{JSONRPC: "2.0", Method: method, Params: mustMarshal(params)})
+}
+
+func (c *client) send(env rpcEnvelope) error {
+	body, err := json.Marshal(env)
+	if err != nil {
+		return err
+	}
+
+	c.wMu.Lock()
+	defer c.wMu.Unlock()
+	if _, err := fmt.Fprintf(c.w, "Content-Length: %d\r\n\r\n", len(body)); err != nil {
+		return err
+	}
+	_, err = c.w.Write(body)
+	return err
+}
+
+func (c *client) readLoop(r *bufio.Reader) {
+	for {
+		length, err := readContentLength(r)
+		if err != nil {
+			return
+		}
+		body := make([]byte, length)
+		if _, err := io.ReadFull(r, body); err != nil {
+			return
+		}
+
+		var env rpcEnvelope
+		if err := json.Unmarshal(body, &env); err != nil {
+			continue
+		}
+
+		switch {
+		case env.ID != nil && env.Method == "":
+			// A response to one of our requests.
+			if ch, ok := c.pending.Load(*env.ID); ok {
+				ch.(chan rpcEnvelope) <- env
+			}
+		case env.ID != nil && env.Method != "":
+			// A server-to-client request; we don't support any of these
+			// (e.g. workspace/configuration), so answer with a null result
+			// rather than leaving the server waiting.
+			_ = c.send(rpcEnvelope{JSONRPC: "2.0", ID: env.ID, Result: json.RawMessage("null")})
+		default:
+			if c.onNotify != nil {
+				c.onNotify(env.Method, env.Params)
+			}
+		}
+	}
+}
+
+// readContentLength reads LSP headers up to the blank line and returns the
+// Content-Length value.
+func readContentLength(r *bufio.Reader) (int, error) {
+	length := -1
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return 0, err
+		}
+		line = strings.TrimRight(line, "\r\n")
+		if line == "" {
+			break
+		}
+		name, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		if strings.EqualFold(strings.TrimSpace(name), "Content-Length") {
+			length, err = strconv.Atoi(strings.TrimSpace(value))
+			if err != nil {
+				return 0, err
+			}
+		}
+	}
+	if length < 0 {
+		return 0, fmt.Errorf("lsp: missing Content-Length header")
+	}
+	return length, nil
+}
+
+func mustMarshal(v interface{}) json.RawMessage {
+	if v == nil {
+		return nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil
+	}
+	return data
+}
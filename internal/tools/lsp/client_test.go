@@ -0,0 +1,107 @@
+package lsp
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"testing"
+	"time"
+)
+
+// fakeServer reads one LSP-framed request off r, and can write a matching
+// response (or an unsolicited notification) to w.
+type fakeServer struct {
+	r *bufio.Reader
+	w io.Writer
+}
+
+func newFakeServer(r io.Reader, w io.Writer) *fakeServer {
+	return &fakeServer{r: bufio.NewReader(r), w: w}
+}
+
+func (f *fakeServer) readMessage() (rpcEnvelope, error) {
+	length, err := readContentLength(f.r)
+	if err != nil {
+		return rpcEnvelope{}, err
+	}
+	body := make([]byte, length)
+	if _, err := io.ReadFull(f.r, body); err != nil {
+		return rpcEnvelope{}, err
+	}
+	var env rpcEnvelope
+	err = json.Unmarshal(body, &env)
+	return env, err
+}
+
+func (f *fakeServer) writeMessage(env rpcEnvelope) error {
+	body, err := json.Marshal(env)
+	if err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(f.w, "Content-Length: %d\r\n\r\n", len(body)); err != nil {
+		return err
+	}
+	_, err = f.w.Write(body)
+	return err
+}
+
+func TestClientCallRoundTrip(t *testing.T) {
+	clientToServerR, clientToServerW := io.Pipe()
+	serverToClientR, serverToClientW := io.Pipe()
+
+	server := newFakeServer(clientToServerR, serverToClientW)
+	c := newClient(clientToServerW, serverToClientR, nil)
+
+	go func() {
+		req, err := server.readMessage()
+		if err != nil || req.Method != "ping" {
+			return
+		}
+		result, _ := json.Marshal(map[string]string{"pong": "ok"})
+		_ = server.writeMessage(rpcEnvelope{JSONRPC: "2.0", ID: req.ID, Result: result})
+	}()
+
+	var result struct {
+		Pong string `json:"pong"`
+	}
+	done := make(chan error, 1)
+	go func() { done <- c.Call("ping", nil, &result) }()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("Call returned error: %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for Call to return")
+	}
+
+	if result.Pong != "ok" {
+		t.Errorf("result.Pong = %q, want %q", result.Pong, "ok")
+	}
+}
+
+func TestClientNotification(t *testing.T) {
+	clientToServerR, clientToServerW := io.Pipe()
+	serverToClientR, serverToClientW := io.Pipe()
+
+	received := make(chan string, 1)
+	_ = newClient(clientToServerW, serverToClientR, func(method string, params json.RawMessage) {
+		received <- method
+	})
+	server := newFakeServer(clientToServerR, serverToClientW)
+
+	go func() {
+		_ = server.writeMessage(rpcEnvelope{JSONRPC: "2.0", Method: "textDocument/publishDiagnostics"})
+	}()
+
+	select {
+	case method := <-received:
+		if method != "textDocument/publishDiagnostics" {
+			t.Errorf("method = %q, want publishDiagnostics", method)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for notification")
+	}
+}
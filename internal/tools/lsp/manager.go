@@ -0,0 +1,376 @@
+package lsp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// ServerConfig describes how to launch the language server for one
+// language, as configured under FileConfig.
+type ServerConfig struct {
+	Command               string          `json:"command"`
+	Args                  []string        `json:"args,omitempty"`
+	InitializationOptions json.RawMessage `json:"initializationOptions,omitempty"`
+}
+
+// extensionLanguages maps a file extension to the LSP language identifier
+// used for textDocument/didOpen and to pick a ServerConfig.
+var extensionLanguages = map[string]string{
+	".go":  "go",
+	".py":  "python",
+	".ts":  "typescript",
+	".tsx": "typescriptreact",
+	".js":  "javascript",
+	".jsx": "javascriptreact",
+	".rs":  "rust",
+	".c":   "c",
+	".h":   "c",
+	".cpp": "cpp",
+	".hpp": "cpp",
+}
+
+// LanguageForPath returns the LSP language identifier for path based on its
+// extension, or "" if it isn't recognized.
+func LanguageForPath(path string) string {
+	return extensionLanguages[filepath.Ext(path)]
+}
+
+// diagnosticsWaitTimeout bounds how long the Diagnostics tool waits for a
+// server to push textDocument/publishDiagnostics after a document is opened.
+const diagnosticsWaitTimeout = 5 * time.Second
+
+// Manager spawns and keeps alive one language server process per
+// (language, workspace root) pair, tracks open documents, and multiplexes
+// requests against the right server.
+type Manager struct {
+	configs map[string]ServerConfig
+
+	mu      sync.Mutex
+	servers map[string]*server // key: language + "\x00" + root
+}
+
+// NewManager creates a Manager that launches servers per configs, keyed by
+// LSP language identifier (e.g. "go" -> gopls, "python" -> pyright).
+func NewManager(configs map[string]ServerConfig) *Manager {
+	return &Manager{
+		configs: configs,
+		servers: make(map[string]*server),
+	}
+}
+
+// server is a single running language server process plus the documents
+// we've opened on it.
+type server struct {
+	cmd    *exec.Cmd
+	client *client
+	root   string
+
+	mu          sync.Mutex
+	openDocs    map[string]int // uri -> version
+	diagnostics map[string]chan []Diagnostic
+}
+
+// getServer returns the (lazily started, initialized) server for language
+// rooted at root, starting it on first use.
+func (m *Manager) getServer(ctx context.Context, language, root string) (*server, error) {
+	cfg, ok := m.configs[language]
+	if !ok {
+		return nil, fmt.Errorf("lsp: no server configured for language %q", language)
+	}
+
+	key := language + "\x00" + root
+	m.mu.Lock()
+	if s, ok := m.servers[key]; ok {
+		m.mu.Unlock()
+		return s, nil
+	}
+	m.mu.Unlock()
+
+	s, err := startServer(ctx, cfg, root)
+	if err != nil {
+		return nil, err
+	}
+
+	m.mu.Lock()
+	if existing, ok := m.servers[key]; ok {
+		m.mu.Unlock()
+		s.shutdown()
+		return existing, nil
+	}
+	m.servers[key] = s
+	m.mu.Unlock()
+
+	return s, nil
+}
+
+func startServer(ctx context.Context, cfg ServerConfig, root string) (*server, error) {
+	cmd := exec.CommandContext(ctx, cfg.Command, cfg.Args...)
+	cmd.Dir = root
+	cmd.Stderr = os.Stderr
+
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+
+	s := &server{
+		cmd:         cmd,
+		root:        root,
+		openDocs:    make(map[string]int),
+		diagnostics: make(map[string]chan []Diagnostic),
+	}
+	s.client = newClient(stdin, stdout, s.handleNotification)
+
+	initParams := map[string]interface{}{
+		"processId": os.Getpid(),
+		"rootUri":   pathToURI(root),
+		"capabilities": map[string]interface{}{
+			"textDocument": map[string]interface{}{
+				"publishDiagnostics": map[string]interface{}{},
+			},
+		},
+	}
+	if len(cfg.InitializationOptions) > 0 {
+		initParams["initializationOptions"] = cfg.InitializationOptions
+	}
+
+	if err := s.client.Call("initialize", initParams, nil); err != nil {
+		s.shutdown()
+		return nil, err
+	}
+	if err := s.client.Notify("initialized", struct{}{}); err != nil {
+		s.shutdown()
+		return nil, err
+	}
+
+	return s, nil
+}
+
+func (s *server) handleNotification(method string, params json.RawMessage) {
+	if method != "textDocument/publishDiagnostics" {
+		return
+	}
+	var p publishDiagnosticsParams
+	if err := json.Unmarshal(params, &p); err != nil {
+		return
+	}
+
+	s.mu.Lock()
+	ch, ok := s.diagnostics[p.URI]
+	if !ok {
+		ch = make(chan []Diagnostic, 1)
+		s.diagnostics[p.URI] = ch
+	}
+	s.mu.Unlock()
+
+	select {
+	case ch <- p.Diagnostics:
+	default:
+		// Drain the stale value and replace it with the latest.
+		select {
+		case <-ch:
+		default:
+		}
+		ch <- p.Diagnostics
+	}
+}
+
+func (s *server) shutdown() {
+	_ = s.client.Call("shutdown", nil, nil)
+	_ = s.client.Notify("exit", nil)
+	_ = s.cmd.Process.Kill()
+}
+
+// ensureOpen sends textDocument/didOpen for path the first time it's seen,
+// returning its URI and LSP language identifier.
+func (s *server) ensureOpen(path, language string) (string, error) {
+	uri := pathToURI(path)
+
+	s.mu.Lock()
+	_, alreadyOpen := s.openDocs[uri]
+	s.mu.Unlock()
+	if alreadyOpen {
+		return uri, nil
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+
+	s.mu.Lock()
+	s.openDocs[uri] = 1
+	if _, ok := s.diagnostics[uri]; !ok {
+		s.diagnostics[uri] = make(chan []Diagnostic, 1)
+	}
+	s.mu.Unlock()
+
+	err = s.client.Notify("textDocument/didOpen", map[string]interface{}{
+		"textDocument": TextDocumentItem{
+			URI:        uri,
+			LanguageID: language,
+			Version:    1,
+			Text:       string(content),
+		},
+	})
+	return uri, err
+}
+
+// resolve locates (or starts) the server responsible for path and ensures
+// the document is open on it, returning the server and the document's URI.
+func (m *Manager) resolve(ctx context.Context, path string) (*server, string, error) {
+	language := LanguageForPath(path)
+	if language == "" {
+		return nil, "", fmt.Errorf("lsp: no language server configured for file type of %q", path)
+	}
+
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return nil, "", err
+	}
+
+	root, err := workspaceRoot(absPath)
+	if err != nil {
+		return nil, "", err
+	}
+
+	s, err := m.getServer(ctx, language, root)
+	if err != nil {
+		return nil, "", err
+	}
+
+	uri, err := s.ensureOpen(absPath, language)
+	if err != nil {
+		return nil, "", err
+	}
+	return s, uri, nil
+}
+
+// Definition resolves the symbol at (line, character) - both 1-based - in
+// path to its definition location(s).
+func (m *Manager) Definition(ctx context.Context, path string, line, character int) ([]Location, error) {
+	s, uri, err := m.resolve(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	var result []Location
+	err = s.client.Call("textDocument/definition", TextDocumentPositionParams{
+		TextDocument: TextDocumentIdentifier{URI: uri},
+		Position:     toLSPPosition(line, character),
+	}, &result)
+	return result, err
+}
+
+// References finds every reference to the symbol at (line, character) in
+// path, optionally including the declaration itself.
+func (m *Manager) References(ctx context.Context, path string, line, character int, includeDeclaration bool) ([]Location, error) {
+	s, uri, err := m.resolve(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	var result []Location
+	err = s.client.Call("textDocument/references", ReferenceParams{
+		TextDocumentPositionParams: TextDocumentPositionParams{
+			TextDocument: TextDocumentIdentifier{URI: uri},
+			Position:     toLSPPosition(line, character),
+		},
+		Context: ReferenceContext{IncludeDeclaration: includeDeclaration},
+	}, &result)
+	return result, err
+}
+
+// Hover fetches the hover text for the symbol at (line, character) in path.
+func (m *Manager) Hover(ctx context.Context, path string, line, character int) (*Hover, error) {
+	s, uri, err := m.resolve(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	var result *Hover
+	err = s.client.Call("textDocument/hover", TextDocumentPositionParams{
+		TextDocument: TextDocumentIdentifier{URI: uri},
+		Position:     toLSPPosition(line, character),
+	}, &result)
+	return result, err
+}
+
+// Diagnostics returns the most recent diagnostics published for path,
+// opening the document (which triggers a fresh publish) if needed.
+func (m *Manager) Diagnostics(ctx context.Context, path string) ([]Diagnostic, error) {
+	s, uri, err := m.resolve(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	ch := s.diagnostics[uri]
+	s.mu.Unlock()
+
+	select {
+	case diags := <-ch:
+		// Put it back so a second call in quick succession still sees it.
+		select {
+		case ch <- diags:
+		default:
+		}
+		return diags, nil
+	case <-time.After(diagnosticsWaitTimeout):
+		return nil, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// WorkspaceSymbol searches for symbols matching query across the workspace
+// rooted at root, using the server for the given language.
+func (m *Manager) WorkspaceSymbol(ctx context.Context, root, language, query string) ([]SymbolInformation, error) {
+	absRoot, err := filepath.Abs(root)
+	if err != nil {
+		return nil, err
+	}
+	s, err := m.getServer(ctx, language, absRoot)
+	if err != nil {
+		return nil, err
+	}
+	var result []SymbolInformation
+	err = s.client.Call("workspace/symbol", map[string]interface{}{"query": query}, &result)
+	return result, err
+}
+
+// workspaceRoot walks up from a file looking for a directory that looks
+// like a project root (a .git directory), falling back to the file's own
+// directory if none is found.
+func workspaceRoot(absPath string) (string, error) {
+	dir := filepath.Dir(absPath)
+	for {
+		if info, err := os.Stat(filepath.Join(dir, ".git")); err == nil && info.IsDir() {
+			return dir, nil
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return filepath.Dir(absPath), nil
+		}
+		dir = parent
+	}
+}
+
+func toLSPPosition(line, character int) Position {
+	return Position{Line: line - 1, Character: character - 1}
+}
+
+func pathToURI(path string) string {
+	return (&url.URL{Scheme: "file", Path: filepath.ToSlash(path)}).String()
+}
@@ -0,0 +1,54 @@
+package lsp
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLanguageForPath(t *testing.T) {
+	cases := map[string]string{
+		"main.go":       "go",
+		"script.py":     "python",
+		"app.tsx":       "typescriptreact",
+		"unknown.xyzzy": "",
+	}
+	for path, want := range cases {
+		if got := LanguageForPath(path); got != want {
+			t.Errorf("LanguageForPath(%q) = %q, want %q", path, got, want)
+		}
+	}
+}
+
+func TestWorkspaceRoot(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "lspworkspacetest")
+	if err != nil {
+		t.Fatalf("Failed to create temp directory: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	if err := os.MkdirAll(filepath.Join(tmpDir, ".git"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	nested := filepath.Join(tmpDir, "pkg", "sub")
+	if err := os.MkdirAll(nested, 0755); err != nil {
+		t.Fatal(err)
+	}
+	file := filepath.Join(nested, "file.go")
+	os.WriteFile(file, []byte("package sub"), 0644)
+
+	root, err := workspaceRoot(file)
+	if err != nil {
+		t.Fatalf("workspaceRoot returned error: %v", err)
+	}
+	if root != tmpDir {
+		t.Errorf("workspaceRoot(%q) = %q, want %q", file, root, tmpDir)
+	}
+}
+
+func TestToLSPPosition(t *testing.T) {
+	pos := toLSPPosition(1, 1)
+	if pos.Line != 0 || pos.Character != 0 {
+		t.Errorf("toLSPPosition(1, 1) = %+v, want zero position", pos)
+	}
+}
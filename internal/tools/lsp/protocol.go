@@ -0,0 +1,93 @@
+// Package lsp implements a minimal Language Server Protocol client over
+// stdio, plus a Manager that keeps one server process alive per workspace
+// root and multiplexes JSON-RPC requests against it. It only implements the
+// subset of the protocol the code-intelligence tools (Definition,
+// References, Hover, Diagnostics, workspace/symbol) need.
+package lsp
+
+// Position is a zero-based line/character offset, as LSP defines it. The
+// tools convert from the 1-based line/character the caller supplies.
+type Position struct {
+	Line      int `json:"line"`
+	Character int `json:"character"`
+}
+
+// Range is a span between two positions.
+type Range struct {
+	Start Position `json:"start"`
+	End   Position `json:"end"`
+}
+
+// Location identifies a range within a document.
+type Location struct {
+	URI   string `json:"uri"`
+	Range Range  `json:"range"`
+}
+
+// TextDocumentIdentifier identifies an open document by URI.
+type TextDocumentIdentifier struct {
+	URI string `json:"uri"`
+}
+
+// TextDocumentItem is the payload LSP expects for textDocument/didOpen.
+type TextDocumentItem struct {
+	URI        string `json:"uri"`
+	LanguageID string `json:"languageId"`
+	Version    int    `json:"version"`
+	Text       string `json:"text"`
+}
+
+// TextDocumentPositionParams is the common shape of definition/references/
+// hover requests: a document plus a position within it.
+type TextDocumentPositionParams struct {
+	TextDocument TextDocumentIdentifier `json:"textDocument"`
+	Position     Position               `json:"position"`
+}
+
+// ReferenceContext controls whether the declaration itself is included in a
+// textDocument/references response.
+type ReferenceContext struct {
+	IncludeDeclaration bool `json:"includeDeclaration"`
+}
+
+// ReferenceParams is the request shape for textDocument/references.
+type ReferenceParams struct {
+	TextDocumentPositionParams
+	Context ReferenceContext `json:"context"`
+}
+
+// MarkupContent is LSP's format for hover/documentation text.
+type MarkupContent struct {
+	Kind  string `json:"kind"`
+	Value string `json:"value"`
+}
+
+// Hover is the response shape for textDocument/hover.
+type Hover struct {
+	Contents MarkupContent `json:"contents"`
+	Range    *Range        `json:"range,omitempty"`
+}
+
+// Diagnostic is a single problem reported by the server, either pushed via
+// textDocument/publishDiagnostics or pulled via textDocument/diagnostic.
+type Diagnostic struct {
+	Range    Range  `json:"range"`
+	Severity int    `json:"severity,omitempty"`
+	Source   string `json:"source,omitempty"`
+	Message  string `json:"message"`
+}
+
+// SymbolInformation is a single result of a workspace/symbol search.
+type SymbolInformation struct {
+	Name          string   `json:"name"`
+	Kind          int      `json:"kind"`
+	Location      Location `json:"location"`
+	ContainerName string   `json:"containerName,omitempty"`
+}
+
+// publishDiagnosticsParams is the payload of a textDocument/publishDiagnostics
+// notification.
+type publishDiagnosticsParams struct {
+	URI         string       `json:"uri"`
+	Diagnostics []Diagnostic `json:"diagnostics"`
+}
@@ -0,0 +1,73 @@
+package tools
+
+import (
+	"context"
+
+	"github.com/cinience/aigo-kode/internal/core"
+	"github.com/cinience/aigo-kode/internal/tools/lsp"
+)
+
+// DefinitionTool implements the Tool interface for resolving where a symbol
+// is defined, via the language server for the file's language.
+type DefinitionTool struct{}
+
+// Name returns the tool name
+func (t *DefinitionTool) Name() string {
+	return "Definition"
+}
+
+// Description returns the tool description
+func (t *DefinitionTool) Description() string {
+	return "Finds the definition of the symbol at a file/line/character position"
+}
+
+// DefinitionToolOutput defines the output structure for DefinitionTool
+type DefinitionToolOutput struct {
+	Locations []lsp.Location `json:"locations"`
+	Error     string         `json:"error,omitempty"`
+}
+
+// Execute executes the definition lookup
+func (t *DefinitionTool) Execute(ctx context.Context, input map[string]interface{}) (interface{}, error) {
+	path, line, character, err := lspPosition(input)
+	if err != nil {
+		return nil, err
+	}
+
+	locations, err := defaultLSPManager().Definition(ctx, path, line, character)
+	if err != nil {
+		return &DefinitionToolOutput{Error: err.Error()}, nil
+	}
+	return &DefinitionToolOutput{Locations: locations}, nil
+}
+
+// ValidateInput validates the input parameters
+func (t *DefinitionTool) ValidateInput(input map[string]interface{}) error {
+	_, _, _, err := lspPosition(input)
+	return err
+}
+
+// Arguments returns the JSON schema fragment describing DefinitionTool's input.
+func (t *DefinitionTool) Arguments() string {
+	return "{" + lspPositionArguments + "\n\t}"
+}
+
+// OutputSchema returns the JSON Schema for DefinitionToolOutput
+func (t *DefinitionTool) OutputSchema() string {
+	return `{"type":"object","properties":{"locations":{"type":"array","items":` + lspLocationSchema + `},"error":{"type":"string"}}}`
+}
+
+// IsReadOnly returns whether the tool is read-only
+func (t *DefinitionTool) IsReadOnly() bool {
+	return true
+}
+
+// RequiresPermission checks if permission is needed
+func (t *DefinitionTool) RequiresPermission(input map[string]interface{}) bool {
+	return true
+}
+
+// NewDefinitionTool creates a new DefinitionTool
+func NewDefinitionTool() core.Tool {
+	return &DefinitionTool{}
+}
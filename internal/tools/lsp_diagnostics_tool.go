@@ -0,0 +1,85 @@
+package tools
+
+import (
+	"context"
+	"errors"
+
+	"github.com/cinience/aigo-kode/internal/core"
+	"github.com/cinience/aigo-kode/internal/tools/lsp"
+)
+
+// DiagnosticsTool implements the Tool interface for fetching the
+// diagnostics (errors/warnings) a language server reports for a file.
+type DiagnosticsTool struct{}
+
+// Name returns the tool name
+func (t *DiagnosticsTool) Name() string {
+	return "Diagnostics"
+}
+
+// Description returns the tool description
+func (t *DiagnosticsTool) Description() string {
+	return "Fetches the diagnostics (errors/warnings) a language server reports for a file"
+}
+
+// DiagnosticsToolOutput defines the output structure for DiagnosticsTool
+type DiagnosticsToolOutput struct {
+	Diagnostics []lsp.Diagnostic `json:"diagnostics"`
+	Error       string           `json:"error,omitempty"`
+}
+
+// Execute executes the diagnostics lookup
+func (t *DiagnosticsTool) Execute(ctx context.Context, input map[string]interface{}) (interface{}, error) {
+	path, ok := input["file_path"].(string)
+	if !ok || path == "" {
+		return nil, errors.New("file_path is required and must be a string")
+	}
+
+	diagnostics, err := defaultLSPManager().Diagnostics(ctx, path)
+	if err != nil {
+		return &DiagnosticsToolOutput{Error: err.Error()}, nil
+	}
+	return &DiagnosticsToolOutput{Diagnostics: diagnostics}, nil
+}
+
+// ValidateInput validates the input parameters
+func (t *DiagnosticsTool) ValidateInput(input map[string]interface{}) error {
+	path, ok := input["file_path"]
+	if !ok {
+		return errors.New("file_path is required")
+	}
+	if s, ok := path.(string); !ok || s == "" {
+		return errors.New("file_path must be a non-empty string")
+	}
+	return nil
+}
+
+// Arguments returns the JSON schema fragment describing DiagnosticsTool's input.
+func (t *DiagnosticsTool) Arguments() string {
+	return `{
+		"file_path": {
+			"type": "string",
+			"description": "Path to the source file"
+		}
+	}`
+}
+
+// OutputSchema returns the JSON Schema for DiagnosticsToolOutput
+func (t *DiagnosticsTool) OutputSchema() string {
+	return `{"type":"object","properties":{"diagnostics":{"type":"array","items":{"type":"object","properties":{"range":{"type":"object"},"severity":{"type":"integer"},"source":{"type":"string"},"message":{"type":"string"}}}},"error":{"type":"string"}}}`
+}
+
+// IsReadOnly returns whether the tool is read-only
+func (t *DiagnosticsTool) IsReadOnly() bool {
+	return true
+}
+
+// RequiresPermission checks if permission is needed
+func (t *DiagnosticsTool) RequiresPermission(input map[string]interface{}) bool {
+	return true
+}
+
+// NewDiagnosticsTool creates a new DiagnosticsTool
+func NewDiagnosticsTool() core.Tool {
+	return &DiagnosticsTool{}
+}
@@ -0,0 +1,73 @@
+package tools
+
+import (
+	"context"
+
+	"github.com/cinience/aigo-kode/internal/core"
+	"github.com/cinience/aigo-kode/internal/tools/lsp"
+)
+
+// HoverTool implements the Tool interface for fetching hover documentation
+// for the symbol at a file/line/character position.
+type HoverTool struct{}
+
+// Name returns the tool name
+func (t *HoverTool) Name() string {
+	return "Hover"
+}
+
+// Description returns the tool description
+func (t *HoverTool) Description() string {
+	return "Fetches hover documentation for the symbol at a file/line/character position"
+}
+
+// HoverToolOutput defines the output structure for HoverTool
+type HoverToolOutput struct {
+	Hover *lsp.Hover `json:"hover,omitempty"`
+	Error string     `json:"error,omitempty"`
+}
+
+// Execute executes the hover lookup
+func (t *HoverTool) Execute(ctx context.Context, input map[string]interface{}) (interface{}, error) {
+	path, line, character, err := lspPosition(input)
+	if err != nil {
+		return nil, err
+	}
+
+	hover, err := defaultLSPManager().Hover(ctx, path, line, character)
+	if err != nil {
+		return &HoverToolOutput{Error: err.Error()}, nil
+	}
+	return &HoverToolOutput{Hover: hover}, nil
+}
+
+// ValidateInput validates the input parameters
+func (t *HoverTool) ValidateInput(input map[string]interface{}) error {
+	_, _, _, err := lspPosition(input)
+	return err
+}
+
+// Arguments returns the JSON schema fragment describing HoverTool's input.
+func (t *HoverTool) Arguments() string {
+	return "{" + lspPositionArguments + "\n\t}"
+}
+
+// OutputSchema returns the JSON Schema for HoverToolOutput
+func (t *HoverTool) OutputSchema() string {
+	return `{"type":"object","properties":{"hover":{"type":"object","properties":{"contents":{"type":"object","properties":{"kind":{"type":"string"},"value":{"type":"string"}}},"range":{"type":"object"}}},"error":{"type":"string"}}}`
+}
+
+// IsReadOnly returns whether the tool is read-only
+func (t *HoverTool) IsReadOnly() bool {
+	return true
+}
+
+// RequiresPermission checks if permission is needed
+func (t *HoverTool) RequiresPermission(input map[string]interface{}) bool {
+	return true
+}
+
+// NewHoverTool creates a new HoverTool
+func NewHoverTool() core.Tool {
+	return &HoverTool{}
+}
@@ -0,0 +1,98 @@
+package tools
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/cinience/aigo-kode/internal/config"
+	"github.com/cinience/aigo-kode/internal/tools/lsp"
+)
+
+var (
+	lspManagerOnce sync.Once
+	lspManager     *lsp.Manager
+)
+
+// defaultLSPManager lazily builds the lsp.Manager shared by every
+// code-intelligence tool, reading server commands from the user's global
+// config (~/.go-anon-kode/config.json, see config.GlobalConfig.LSPServers).
+func defaultLSPManager() *lsp.Manager {
+	lspManagerOnce.Do(func() {
+		lspManager = lsp.NewManager(loadLSPServerConfigs())
+	})
+	return lspManager
+}
+
+func loadLSPServerConfigs() map[string]lsp.ServerConfig {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return nil
+	}
+	cfg, err := config.NewFileConfig(filepath.Join(homeDir, ".go-anon-kode"))
+	if err != nil {
+		return nil
+	}
+	global, err := cfg.GetGlobalConfig()
+	if err != nil {
+		return nil
+	}
+
+	servers := make(map[string]lsp.ServerConfig, len(global.LSPServers))
+	for language, sc := range global.LSPServers {
+		servers[language] = lsp.ServerConfig{
+			Command:               sc.Command,
+			Args:                  sc.Args,
+			InitializationOptions: sc.InitializationOptions,
+		}
+	}
+	return servers
+}
+
+// lspPosition extracts the required file_path/line/character parameters
+// shared by Definition, References, and Hover.
+func lspPosition(input map[string]interface{}) (path string, line, character int, err error) {
+	path, ok := input["file_path"].(string)
+	if !ok || path == "" {
+		return "", 0, 0, errors.New("file_path is required and must be a string")
+	}
+
+	lineVal, ok := input["line"].(float64)
+	if !ok || lineVal < 1 {
+		return "", 0, 0, errors.New("line is required and must be a positive number (1-based)")
+	}
+
+	charVal, ok := input["character"].(float64)
+	if !ok || charVal < 1 {
+		return "", 0, 0, errors.New("character is required and must be a positive number (1-based)")
+	}
+
+	return path, int(lineVal), int(charVal), nil
+}
+
+// errNotABool builds the validation error used whenever a boolean
+// parameter is present but holds some other type.
+func errNotABool(field string) error {
+	return errors.New(field + " must be a boolean")
+}
+
+// lspPositionArguments is the JSON schema fragment shared by Definition,
+// References, and Hover.
+const lspPositionArguments = `
+		"file_path": {
+			"type": "string",
+			"description": "Path to the source file"
+		},
+		"line": {
+			"type": "number",
+			"description": "1-based line number"
+		},
+		"character": {
+			"type": "number",
+			"description": "1-based character offset within the line"
+		}`
+
+// lspLocationSchema is the JSON Schema fragment for an lsp.Location,
+// shared by the output schemas of Definition and References.
+const lspLocationSchema = `{"type":"object","properties":{"uri":{"type":"string"},"range":{"type":"object","properties":{"start":{"type":"object","properties":{"line":{"type":"integer"},"character":{"type":"integer"}}},"end":{"type":"object","properties":{"line":{"type":"integer"},"character":{"type":"integer"}}}}}}}`
@@ -0,0 +1,90 @@
+package tools
+
+import (
+	"context"
+
+	"github.com/cinience/aigo-kode/internal/core"
+	"github.com/cinience/aigo-kode/internal/tools/lsp"
+)
+
+// ReferencesTool implements the Tool interface for finding every reference
+// to the symbol at a file/line/character position.
+type ReferencesTool struct{}
+
+// Name returns the tool name
+func (t *ReferencesTool) Name() string {
+	return "References"
+}
+
+// Description returns the tool description
+func (t *ReferencesTool) Description() string {
+	return "Finds every reference to the symbol at a file/line/character position"
+}
+
+// ReferencesToolOutput defines the output structure for ReferencesTool
+type ReferencesToolOutput struct {
+	Locations []lsp.Location `json:"locations"`
+	Error     string         `json:"error,omitempty"`
+}
+
+// Execute executes the references lookup
+func (t *ReferencesTool) Execute(ctx context.Context, input map[string]interface{}) (interface{}, error) {
+	path, line, character, err := lspPosition(input)
+	if err != nil {
+		return nil, err
+	}
+
+	includeDeclaration := true
+	if v, ok := input["include_declaration"].(bool); ok {
+		includeDeclaration = v
+	}
+
+	locations, err := defaultLSPManager().References(ctx, path, line, character, includeDeclaration)
+	if err != nil {
+		return &ReferencesToolOutput{Error: err.Error()}, nil
+	}
+	return &ReferencesToolOutput{Locations: locations}, nil
+}
+
+// ValidateInput validates the input parameters
+func (t *ReferencesTool) ValidateInput(input map[string]interface{}) error {
+	if _, _, _, err := lspPosition(input); err != nil {
+		return err
+	}
+	if v, ok := input["include_declaration"]; ok {
+		if _, ok := v.(bool); !ok {
+			return errNotABool("include_declaration")
+		}
+	}
+	return nil
+}
+
+// Arguments returns the JSON schema fragment describing ReferencesTool's input.
+func (t *ReferencesTool) Arguments() string {
+	return `{` + lspPositionArguments + `,
+		"include_declaration": {
+			"type": "boolean",
+			"description": "Whether to include the declaration itself (default: true)"
+		}
+	}`
+}
+
+// OutputSchema returns the JSON Schema for ReferencesToolOutput
+func (t *ReferencesTool) OutputSchema() string {
+	return `{"type":"object","properties":{"locations":{"type":"array","items":` + lspLocationSchema + `},"error":{"type":"string"}}}`
+}
+
+// IsReadOnly returns whether the tool is read-only
+func (t *ReferencesTool) IsReadOnly() bool {
+	return true
+}
+
+// RequiresPermission checks if permission is needed
+func (t *ReferencesTool) RequiresPermission(input map[string]interface{}) bool {
+	return true
+}
+
+// NewReferencesTool creates a new ReferencesTool
+func NewReferencesTool() core.Tool {
+	return &ReferencesTool{}
+}
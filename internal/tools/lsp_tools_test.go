@@ -0,0 +1,53 @@
+package tools
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDefinitionToolValidation(t *testing.T) {
+	tool := NewDefinitionTool()
+
+	err := tool.ValidateInput(map[string]interface{}{
+		"file_path": "main.go",
+		"line":      float64(10),
+		"character": float64(5),
+	})
+	assert.NoError(t, err)
+
+	err = tool.ValidateInput(map[string]interface{}{"file_path": "main.go"})
+	assert.Error(t, err)
+
+	err = tool.ValidateInput(map[string]interface{}{
+		"file_path": "main.go",
+		"line":      float64(0),
+		"character": float64(5),
+	})
+	assert.Error(t, err)
+}
+
+func TestDiagnosticsToolValidation(t *testing.T) {
+	tool := NewDiagnosticsTool()
+
+	assert.Error(t, tool.ValidateInput(map[string]interface{}{}))
+	assert.NoError(t, tool.ValidateInput(map[string]interface{}{"file_path": "main.go"}))
+}
+
+func TestWorkspaceSymbolToolInference(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "wssymboltest")
+	if err != nil {
+		t.Fatalf("Failed to create temp directory: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	os.WriteFile(filepath.Join(tmpDir, "go.mod"), []byte("module example\n"), 0644)
+
+	assert.Equal(t, "go", inferWorkspaceLanguage(tmpDir))
+
+	tool := NewWorkspaceSymbolTool()
+	assert.Error(t, tool.ValidateInput(map[string]interface{}{}))
+	assert.NoError(t, tool.ValidateInput(map[string]interface{}{"query": "Foo"}))
+}
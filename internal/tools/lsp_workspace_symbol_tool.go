@@ -0,0 +1,139 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+
+	"github.com/cinience/aigo-kode/internal/core"
+	"github.com/cinience/aigo-kode/internal/tools/lsp"
+)
+
+// WorkspaceSymbolTool implements the Tool interface for fuzzy symbol search
+// across a whole project via a language server's workspace/symbol request.
+type WorkspaceSymbolTool struct{}
+
+// Name returns the tool name
+func (t *WorkspaceSymbolTool) Name() string {
+	return "WorkspaceSymbol"
+}
+
+// Description returns the tool description
+func (t *WorkspaceSymbolTool) Description() string {
+	return "Fuzzy-searches for symbols by name across the workspace"
+}
+
+// WorkspaceSymbolToolOutput defines the output structure for WorkspaceSymbolTool
+type WorkspaceSymbolToolOutput struct {
+	Symbols []lsp.SymbolInformation `json:"symbols"`
+	Error   string                  `json:"error,omitempty"`
+}
+
+// Execute executes the workspace symbol search
+func (t *WorkspaceSymbolTool) Execute(ctx context.Context, input map[string]interface{}) (interface{}, error) {
+	query, ok := input["query"].(string)
+	if !ok || query == "" {
+		return nil, errors.New("query is required and must be a string")
+	}
+
+	root := "."
+	if v, ok := input["root"].(string); ok && v != "" {
+		root = v
+	}
+
+	language, ok := input["language"].(string)
+	if !ok || language == "" {
+		language = inferWorkspaceLanguage(root)
+		if language == "" {
+			return &WorkspaceSymbolToolOutput{Error: "could not infer a language for root; pass the language parameter explicitly"}, nil
+		}
+	}
+
+	symbols, err := defaultLSPManager().WorkspaceSymbol(ctx, root, language, query)
+	if err != nil {
+		return &WorkspaceSymbolToolOutput{Error: err.Error()}, nil
+	}
+	return &WorkspaceSymbolToolOutput{Symbols: symbols}, nil
+}
+
+// inferWorkspaceLanguage guesses the LSP language identifier for root by
+// looking for the usual project marker files.
+func inferWorkspaceLanguage(root string) string {
+	markers := []struct {
+		file     string
+		language string
+	}{
+		{"go.mod", "go"},
+		{"Cargo.toml", "rust"},
+		{"tsconfig.json", "typescript"},
+		{"package.json", "javascript"},
+		{"pyproject.toml", "python"},
+		{"requirements.txt", "python"},
+		{"setup.py", "python"},
+	}
+	for _, m := range markers {
+		if _, err := os.Stat(filepath.Join(root, m.file)); err == nil {
+			return m.language
+		}
+	}
+	return ""
+}
+
+// ValidateInput validates the input parameters
+func (t *WorkspaceSymbolTool) ValidateInput(input map[string]interface{}) error {
+	query, ok := input["query"]
+	if !ok {
+		return errors.New("query is required")
+	}
+	if s, ok := query.(string); !ok || s == "" {
+		return errors.New("query must be a non-empty string")
+	}
+
+	for _, key := range []string{"root", "language"} {
+		if v, ok := input[key]; ok {
+			if _, ok := v.(string); !ok {
+				return errors.New(key + " must be a string")
+			}
+		}
+	}
+	return nil
+}
+
+// Arguments returns the JSON schema fragment describing WorkspaceSymbolTool's input.
+func (t *WorkspaceSymbolTool) Arguments() string {
+	return `{
+		"query": {
+			"type": "string",
+			"description": "Symbol name (or fuzzy fragment) to search for"
+		},
+		"root": {
+			"type": "string",
+			"description": "Workspace root to search (default: current directory)"
+		},
+		"language": {
+			"type": "string",
+			"description": "LSP language identifier to search with (inferred from project files if omitted)"
+		}
+	}`
+}
+
+// OutputSchema returns the JSON Schema for WorkspaceSymbolToolOutput
+func (t *WorkspaceSymbolTool) OutputSchema() string {
+	return `{"type":"object","properties":{"symbols":{"type":"array","items":{"type":"object","properties":{"name":{"type":"string"},"kind":{"type":"integer"},"location":` + lspLocationSchema + `,"containerName":{"type":"string"}}}},"error":{"type":"string"}}}`
+}
+
+// IsReadOnly returns whether the tool is read-only
+func (t *WorkspaceSymbolTool) IsReadOnly() bool {
+	return true
+}
+
+// RequiresPermission checks if permission is needed
+func (t *WorkspaceSymbolTool) RequiresPermission(input map[string]interface{}) bool {
+	return true
+}
+
+// NewWorkspaceSymbolTool creates a new WorkspaceSymbolTool
+func NewWorkspaceSymbolTool() core.Tool {
+	return &WorkspaceSymbolTool{}
+}
@@ -0,0 +1,36 @@
+package tools
+
+import (
+	"context"
+	"log"
+
+	"github.com/cinience/aigo-kode/internal/config"
+	"github.com/cinience/aigo-kode/internal/core"
+	"github.com/cinience/aigo-kode/internal/mcp"
+)
+
+// RegisterMCPServers connects to every configured MCP server, performs its
+// tools/list handshake, and registers each tool it advertises under the
+// namespaced name internal/mcp.Tool gives it. A server that fails to
+// connect or list its tools is skipped (logged, not fatal), so one bad
+// config entry doesn't take down every other tool.
+func (r *ToolRegistry) RegisterMCPServers(servers map[string]config.MCPServerConfig) {
+	for name, cfg := range servers {
+		client, err := mcp.NewClient(name, cfg)
+		if err != nil {
+			log.Printf("mcp: %v", err)
+			continue
+		}
+
+		defs, err := client.ListTools(context.Background())
+		if err != nil {
+			log.Printf("mcp: listing tools from %q: %v", name, err)
+			continue
+		}
+
+		for _, def := range defs {
+			tool := mcp.NewTool(client, name, def)
+			r.RegisterTool(tool.Name(), func() core.Tool { return tool })
+		}
+	}
+}
@@ -1,7 +1,10 @@
 package tools
 
 import (
+	"fmt"
+
 	"github.com/cinience/aigo-kode/internal/core"
+	"github.com/cinience/aigo-kode/internal/sandbox"
 )
 
 // ToolRegistry manages the collection of available tools
@@ -38,6 +41,18 @@ func (r *ToolRegistry) GetAllTools() []core.Tool {
 	return tools
 }
 
+// GetTools returns the registered tools named in names, in that order,
+// skipping any name that isn't registered.
+func (r *ToolRegistry) GetTools(names []string) []core.Tool {
+	tools := make([]core.Tool, 0, len(names))
+	for _, name := range names {
+		if tool := r.GetTool(name); tool != nil {
+			tools = append(tools, tool)
+		}
+	}
+	return tools
+}
+
 // GetReadOnlyTools returns all read-only tools
 func (r *ToolRegistry) GetReadOnlyTools() []core.Tool {
 	tools := make([]core.Tool, 0, len(r.tools))
@@ -50,6 +65,61 @@ func (r *ToolRegistry) GetReadOnlyTools() []core.Tool {
 	return tools
 }
 
+// OpenAIToolDef is the function-calling tool definition shape OpenAI's API
+// expects. It's kept provider-agnostic here (a plain string, not an SDK
+// type) so callers that just need the schema don't have to import an
+// OpenAI client package.
+type OpenAIToolDef struct {
+	Name        string
+	Description string
+	Parameters  string // full JSON Schema document
+}
+
+// ToOpenAITools converts every registered tool into an OpenAI
+// function-calling tool definition.
+func (r *ToolRegistry) ToOpenAITools() []OpenAIToolDef {
+	all := r.GetAllTools()
+	defs := make([]OpenAIToolDef, len(all))
+	for i, tool := range all {
+		defs[i] = OpenAIToolDef{
+			Name:        tool.Name(),
+			Description: tool.Description(),
+			Parameters:  fullSchema(tool),
+		}
+	}
+	return defs
+}
+
+// AnthropicToolDef is the tool definition shape Anthropic's Messages API
+// expects.
+type AnthropicToolDef struct {
+	Name        string
+	Description string
+	InputSchema string // full JSON Schema document
+}
+
+// ToAnthropicTools converts every registered tool into an Anthropic tool
+// definition.
+func (r *ToolRegistry) ToAnthropicTools() []AnthropicToolDef {
+	all := r.GetAllTools()
+	defs := make([]AnthropicToolDef, len(all))
+	for i, tool := range all {
+		defs[i] = AnthropicToolDef{
+			Name:        tool.Name(),
+			Description: tool.Description(),
+			InputSchema: fullSchema(tool),
+		}
+	}
+	return defs
+}
+
+// fullSchema wraps a tool's Arguments() properties fragment into a
+// complete JSON Schema object, the same wrapping the provider model
+// clients have always applied at request time.
+func fullSchema(tool core.Tool) string {
+	return fmt.Sprintf(`{"type":"object","properties":%s}`, tool.Arguments())
+}
+
 // DefaultToolRegistry creates and returns a registry with all standard tools
 func DefaultToolRegistry() *ToolRegistry {
 	registry := NewToolRegistry()
@@ -59,10 +129,29 @@ func DefaultToolRegistry() *ToolRegistry {
 	registry.RegisterTool("FileRead", NewFileReadTool)
 	registry.RegisterTool("FileWrite", NewFileWriteTool)
 	registry.RegisterTool("FileEdit", NewFileEditTool)
+	registry.RegisterTool("FileRevert", NewFileRevertTool)
+	registry.RegisterTool("FilePatch", NewFilePatchTool)
 	registry.RegisterTool("Glob", NewGlobTool)
 	registry.RegisterTool("Grep", NewGrepTool)
 	registry.RegisterTool("LS", NewLSTool)
 	registry.RegisterTool("Think", NewThinkTool)
+	registry.RegisterTool("Definition", NewDefinitionTool)
+	registry.RegisterTool("References", NewReferencesTool)
+	registry.RegisterTool("Hover", NewHoverTool)
+	registry.RegisterTool("Diagnostics", NewDiagnosticsTool)
+	registry.RegisterTool("WorkspaceSymbol", NewWorkspaceSymbolTool)
+
+	return registry
+}
 
+// DefaultToolRegistryWithBashPolicy creates the standard registry but
+// replaces the Bash tool's factory with one bound to policy and limits,
+// for callers (the CLI's session/project config wiring) that need the
+// sandbox to enforce more than the Bash tool's built-in defaults.
+func DefaultToolRegistryWithBashPolicy(policy *sandbox.Policy, limits sandbox.ResourceLimits) *ToolRegistry {
+	registry := DefaultToolRegistry()
+	registry.RegisterTool("Bash", func() core.Tool {
+		return NewBashToolWithPolicy(policy, limits)
+	})
 	return registry
 }
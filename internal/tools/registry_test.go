@@ -0,0 +1,23 @@
+package tools
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetToolsFiltersAndPreservesOrder(t *testing.T) {
+	registry := DefaultToolRegistry()
+
+	selected := registry.GetTools([]string{"Bash", "FileRead", "NoSuchTool"})
+	assert.Len(t, selected, 2)
+	assert.Equal(t, "Bash", selected[0].Name())
+	assert.Equal(t, "FileRead", selected[1].Name())
+}
+
+func TestGetToolsEmptyNamesReturnsEmpty(t *testing.T) {
+	registry := DefaultToolRegistry()
+
+	selected := registry.GetTools(nil)
+	assert.Len(t, selected, 0)
+}
@@ -0,0 +1,243 @@
+// Package schema generates JSON Schema (draft-07) documents from Go
+// structs via reflection, and validates tool input maps against them. A
+// tool declares its parameters as a struct with `json` and `jsonschema`
+// tags instead of hand-writing a schema fragment and duplicating the
+// equivalent checks in ValidateInput.
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Property describes one field of a generated schema document.
+type Property struct {
+	Type        string   `json:"type"`
+	Description string   `json:"description,omitempty"`
+	Enum        []string `json:"enum,omitempty"`
+	MinLength   int      `json:"minLength,omitempty"`
+	Minimum     *float64 `json:"minimum,omitempty"`
+	Maximum     *float64 `json:"maximum,omitempty"`
+	// Required marks this property as mandatory. It is kept alongside the
+	// property itself (rather than a sibling top-level "required" array)
+	// so it survives round-tripping through Tool.Arguments(), which has
+	// always returned just the properties object.
+	Required bool `json:"required,omitempty"`
+}
+
+// Document is a JSON Schema object describing a tool's input struct.
+type Document struct {
+	Type       string               `json:"type"`
+	Properties map[string]*Property `json:"properties"`
+}
+
+// Generate reflects over v (a struct, or pointer to one) and builds a
+// Document from its exported fields' `json` and `jsonschema` tags.
+// Recognized jsonschema tag keys: required, description, enum (values
+// separated by "|"), minLength, minimum, maximum.
+func Generate(v interface{}) (*Document, error) {
+	t := reflect.TypeOf(v)
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("schema: Generate requires a struct, got %T", v)
+	}
+
+	doc := &Document{Type: "object", Properties: map[string]*Property{}}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" {
+			continue // unexported
+		}
+
+		name := field.Name
+		if jsonTag := field.Tag.Get("json"); jsonTag != "" {
+			tagName := strings.Split(jsonTag, ",")[0]
+			if tagName == "-" {
+				continue
+			}
+			if tagName != "" {
+				name = tagName
+			}
+		}
+
+		prop := &Property{Type: jsonType(field.Type)}
+		for _, part := range strings.Split(field.Tag.Get("jsonschema"), ",") {
+			part = strings.TrimSpace(part)
+			if part == "" {
+				continue
+			}
+			key, value := part, ""
+			if idx := strings.Index(part, "="); idx >= 0 {
+				key, value = part[:idx], part[idx+1:]
+			}
+			switch key {
+			case "required":
+				prop.Required = true
+			case "description":
+				prop.Description = value
+			case "enum":
+				prop.Enum = strings.Split(value, "|")
+			case "minLength":
+				if n, err := strconv.Atoi(value); err == nil {
+					prop.MinLength = n
+				}
+			case "minimum":
+				if f, err := strconv.ParseFloat(value, 64); err == nil {
+					prop.Minimum = &f
+				}
+			case "maximum":
+				if f, err := strconv.ParseFloat(value, 64); err == nil {
+					prop.Maximum = &f
+				}
+			}
+		}
+
+		doc.Properties[name] = prop
+	}
+
+	return doc, nil
+}
+
+// MustGenerate is Generate for package-level schema variables, panicking
+// (at init time, on a programmer error) rather than returning an error.
+func MustGenerate(v interface{}) *Document {
+	doc, err := Generate(v)
+	if err != nil {
+		panic(err)
+	}
+	return doc
+}
+
+func jsonType(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.String:
+		return "string"
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "integer"
+	case reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.Slice, reflect.Array:
+		return "array"
+	default:
+		return "object"
+	}
+}
+
+// JSON returns the full schema document as JSON, suitable for a provider's
+// function-calling "parameters"/"input_schema" field.
+func (d *Document) JSON() string {
+	data, _ := json.Marshal(d)
+	return string(data)
+}
+
+// PropertiesJSON returns just the properties object, matching the format
+// Tool.Arguments() has always returned.
+func (d *Document) PropertiesJSON() string {
+	data, _ := json.Marshal(d.Properties)
+	return string(data)
+}
+
+// Validate checks input against the document: every required property
+// must be present, and present properties must match their declared type,
+// enum, minLength, minimum and maximum.
+func (d *Document) Validate(input map[string]interface{}) error {
+	for name, prop := range d.Properties {
+		val, ok := input[name]
+		if !ok {
+			if prop.Required {
+				return fmt.Errorf("%s is required", name)
+			}
+			continue
+		}
+		if err := prop.validate(name, val); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *Property) validate(name string, val interface{}) error {
+	switch p.Type {
+	case "string":
+		s, ok := val.(string)
+		if !ok {
+			return fmt.Errorf("%s must be a string", name)
+		}
+		if s == "" && p.Required {
+			return fmt.Errorf("%s cannot be empty", name)
+		}
+		if p.MinLength > 0 && len(s) < p.MinLength {
+			return fmt.Errorf("%s must be at least %d characters", name, p.MinLength)
+		}
+		if len(p.Enum) > 0 && !containsString(p.Enum, s) {
+			return fmt.Errorf("%s must be one of %v", name, p.Enum)
+		}
+	case "integer", "number":
+		f, ok := toFloat(val)
+		if !ok {
+			return fmt.Errorf("%s must be a number", name)
+		}
+		if p.Minimum != nil && f < *p.Minimum {
+			return fmt.Errorf("%s must be >= %v", name, *p.Minimum)
+		}
+		if p.Maximum != nil && f > *p.Maximum {
+			return fmt.Errorf("%s must be <= %v", name, *p.Maximum)
+		}
+	case "boolean":
+		if _, ok := val.(bool); !ok {
+			return fmt.Errorf("%s must be a boolean", name)
+		}
+	}
+	return nil
+}
+
+func toFloat(val interface{}) (float64, bool) {
+	switch v := val.(type) {
+	case int:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case float32:
+		return float64(v), true
+	case float64:
+		return v, true
+	default:
+		return 0, false
+	}
+}
+
+func containsString(values []string, target string) bool {
+	for _, v := range values {
+		if v == target {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateFragment validates input against fragment, a properties-only
+// JSON object as returned by Tool.Arguments(). Tools generated with
+// Generate mark required fields inline (see Property.Required) so those
+// are enforced here too; tools that predate this package and don't mark
+// anything required get best-effort type checking only.
+func ValidateFragment(fragment string, input map[string]interface{}) error {
+	if fragment == "" {
+		return nil
+	}
+	properties := map[string]*Property{}
+	if err := json.Unmarshal([]byte(fragment), &properties); err != nil {
+		return fmt.Errorf("schema: invalid arguments fragment: %w", err)
+	}
+	doc := &Document{Type: "object", Properties: properties}
+	return doc.Validate(input)
+}
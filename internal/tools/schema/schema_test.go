@@ -0,0 +1,70 @@
+package schema
+
+import (
+	"strings"
+	"testing"
+)
+
+type testInput struct {
+	Name  string `json:"name" jsonschema:"required,minLength=2,description=A name"`
+	Count int    `json:"count,omitempty" jsonschema:"minimum=1,maximum=10"`
+}
+
+func TestGenerate(t *testing.T) {
+	doc, err := Generate(testInput{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	name, ok := doc.Properties["name"]
+	if !ok {
+		t.Fatal("expected a \"name\" property")
+	}
+	if name.Type != "string" || !name.Required || name.MinLength != 2 {
+		t.Errorf("unexpected name property: %+v", name)
+	}
+
+	count, ok := doc.Properties["count"]
+	if !ok {
+		t.Fatal("expected a \"count\" property")
+	}
+	if count.Type != "integer" || count.Required || count.Minimum == nil || *count.Minimum != 1 {
+		t.Errorf("unexpected count property: %+v", count)
+	}
+}
+
+func TestDocumentValidate(t *testing.T) {
+	doc := MustGenerate(testInput{})
+
+	if err := doc.Validate(map[string]interface{}{"name": "ab"}); err != nil {
+		t.Errorf("expected valid input to pass, got %v", err)
+	}
+	if err := doc.Validate(map[string]interface{}{}); err == nil {
+		t.Error("expected missing required field to fail")
+	}
+	if err := doc.Validate(map[string]interface{}{"name": "a"}); err == nil {
+		t.Error("expected too-short name to fail minLength")
+	}
+	if err := doc.Validate(map[string]interface{}{"name": "ab", "count": 20}); err == nil {
+		t.Error("expected out-of-range count to fail maximum")
+	}
+}
+
+func TestValidateFragment(t *testing.T) {
+	fragment := `{"path": {"type": "string", "description": "a path"}}`
+
+	if err := ValidateFragment(fragment, map[string]interface{}{"path": "/tmp"}); err != nil {
+		t.Errorf("expected valid input to pass, got %v", err)
+	}
+	if err := ValidateFragment(fragment, map[string]interface{}{"path": 5}); err == nil {
+		t.Error("expected wrong type to fail")
+	}
+}
+
+func TestPropertiesJSON(t *testing.T) {
+	doc := MustGenerate(testInput{})
+	fragment := doc.PropertiesJSON()
+	if !strings.Contains(fragment, `"name"`) {
+		t.Errorf("expected properties fragment to contain \"name\", got %s", fragment)
+	}
+}
@@ -5,11 +5,21 @@ import (
 	"errors"
 
 	"github.com/cinience/aigo-kode/internal/core"
+	"github.com/cinience/aigo-kode/internal/tools/schema"
 )
 
 // ThinkTool implements the Tool interface for AI thinking/reasoning
 type ThinkTool struct{}
 
+// ThinkToolInput is ThinkTool's declared input shape. Its jsonschema tags
+// drive both Arguments() and ValidateInput via the schema package, so
+// there's a single source of truth for what this tool accepts.
+type ThinkToolInput struct {
+	Prompt string `json:"prompt" jsonschema:"required,minLength=1,description=The prompt to think through"`
+}
+
+var thinkToolSchema = schema.MustGenerate(ThinkToolInput{})
+
 // Name returns the tool name
 func (t *ThinkTool) Name() string {
 	return "Think"
@@ -40,33 +50,21 @@ func (t *ThinkTool) Execute(ctx context.Context, input map[string]interface{}) (
 	}, nil
 }
 
-// ValidateInput validates the input parameters
+// ValidateInput validates the input parameters against ThinkToolInput's
+// generated schema
 func (t *ThinkTool) ValidateInput(input map[string]interface{}) error {
-	// Check if prompt exists and is a string
-	promptVal, ok := input["prompt"]
-	if !ok {
-		return errors.New("prompt is required")
-	}
-
-	prompt, ok := promptVal.(string)
-	if !ok {
-		return errors.New("prompt must be a string")
-	}
-
-	if prompt == "" {
-		return errors.New("prompt cannot be empty")
-	}
-
-	return nil
+	return thinkToolSchema.Validate(input)
 }
 
+// Arguments returns the JSON Schema properties generated from
+// ThinkToolInput
 func (t *ThinkTool) Arguments() string {
-	return `{
-		"prompt": {
-			"type": "string",
-			"description": "The prompt to think through"
-		}
-	}`
+	return thinkToolSchema.PropertiesJSON()
+}
+
+// OutputSchema returns the JSON Schema for ThinkToolOutput
+func (t *ThinkTool) OutputSchema() string {
+	return `{"type":"object","properties":{"reasoning":{"type":"string"}}}`
 }
 
 // IsReadOnly returns whether the tool is read-only